@@ -26,6 +26,32 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// ArtifactType tells plugins apart from the server software that runs them
+type ArtifactType int32
+
+const (
+	ArtifactType_PLUGIN          ArtifactType = 0
+	ArtifactType_SERVER_SOFTWARE ArtifactType = 1
+)
+
+var ArtifactType_name = map[int32]string{
+	0: "PLUGIN",
+	1: "SERVER_SOFTWARE",
+}
+
+var ArtifactType_value = map[string]int32{
+	"PLUGIN":          0,
+	"SERVER_SOFTWARE": 1,
+}
+
+func (x ArtifactType) String() string {
+	return proto.EnumName(ArtifactType_name, int32(x))
+}
+
+func (ArtifactType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_1b40cafcd4234784, []int{0}
+}
+
 type Category int32
 
 const (
@@ -75,7 +101,7 @@ func (x Category) String() string {
 }
 
 func (Category) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{0}
+	return fileDescriptor_1b40cafcd4234784, []int{1}
 }
 
 type Sort int32
@@ -106,7 +132,7 @@ func (x Sort) String() string {
 }
 
 func (Sort) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{1}
+	return fileDescriptor_1b40cafcd4234784, []int{2}
 }
 
 type User struct {
@@ -222,7 +248,6 @@ func (m *User) GetPurchases() []*Purchase {
 
 type Purchase struct {
 	ObjectId             string   `protobuf:"bytes,1,opt,name=objectId,proto3" json:"objectId,omitempty"`
-	Session              string   `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
 	Complete             bool     `protobuf:"varint,3,opt,name=complete,proto3" json:"complete,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -269,13 +294,6 @@ func (m *Purchase) GetObjectId() string {
 	return ""
 }
 
-func (m *Purchase) GetSession() string {
-	if m != nil {
-		return m.Session
-	}
-	return ""
-}
-
 func (m *Purchase) GetComplete() bool {
 	if m != nil {
 		return m.Complete
@@ -410,19 +428,31 @@ func (m *PaginatePluginsResponse) GetPlugins() []*Plugin {
 }
 
 type Plugin struct {
-	Id                   string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Author               *User           `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
-	Version              string          `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
-	Description          string          `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	Thumbnail            string          `protobuf:"bytes,6,opt,name=thumbnail,proto3" json:"thumbnail,omitempty"`
-	Category             Category        `protobuf:"varint,7,opt,name=category,proto3,enum=api.Category" json:"category,omitempty"`
-	Metadata             *PluginMetadata `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	Premium              *Premium        `protobuf:"bytes,9,opt,name=premium,proto3" json:"premium,omitempty"`
-	LastUpdated          int64           `protobuf:"varint,10,opt,name=lastUpdated,proto3" json:"lastUpdated,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Id          string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Author      *User           `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Version     string          `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Description string          `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Thumbnail   string          `protobuf:"bytes,6,opt,name=thumbnail,proto3" json:"thumbnail,omitempty"`
+	Category    Category        `protobuf:"varint,7,opt,name=category,proto3,enum=api.Category" json:"category,omitempty"`
+	Metadata    *PluginMetadata `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Premium     *Premium        `protobuf:"bytes,9,opt,name=premium,proto3" json:"premium,omitempty"`
+	LastUpdated int64           `protobuf:"varint,10,opt,name=lastUpdated,proto3" json:"lastUpdated,omitempty"`
+	Type        ArtifactType    `protobuf:"varint,11,opt,name=type,proto3,enum=api.ArtifactType" json:"type,omitempty"`
+	// channel is the release channel of the version: stable, beta or alpha
+	Channel string `protobuf:"bytes,12,opt,name=channel,proto3" json:"channel,omitempty"`
+	// channels maps every release channel to its newest version, version is
+	// the newest stable version and empty until there is a stable release
+	Channels map[string]string `protobuf:"bytes,13,rep,name=channels,proto3" json:"channels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// yanked maps every yanked version to the reason it was yanked. In
+	// UpdateYanked an empty reason takes a version off the list
+	Yanked map[string]string `protobuf:"bytes,14,rep,name=yanked,proto3" json:"yanked,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// deprecation is set once the plugin is no longer maintained. In
+	// UpdateDeprecation an empty reason removes it
+	Deprecation          *Deprecation `protobuf:"bytes,15,opt,name=deprecation,proto3" json:"deprecation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *Plugin) Reset()         { *m = Plugin{} }
@@ -528,9 +558,47 @@ func (m *Plugin) GetLastUpdated() int64 {
 	return 0
 }
 
+func (m *Plugin) GetType() ArtifactType {
+	if m != nil {
+		return m.Type
+	}
+	return ArtifactType_PLUGIN
+}
+
+func (m *Plugin) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *Plugin) GetChannels() map[string]string {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *Plugin) GetYanked() map[string]string {
+	if m != nil {
+		return m.Yanked
+	}
+	return nil
+}
+
+func (m *Plugin) GetDeprecation() *Deprecation {
+	if m != nil {
+		return m.Deprecation
+	}
+	return nil
+}
+
 type PluginMetadata struct {
 	Downloads            int64    `protobuf:"varint,1,opt,name=downloads,proto3" json:"downloads,omitempty"`
 	Conflicts            []string `protobuf:"bytes,2,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	Depend               []string `protobuf:"bytes,3,rep,name=depend,proto3" json:"depend,omitempty"`
+	Softdepend           []string `protobuf:"bytes,4,rep,name=softdepend,proto3" json:"softdepend,omitempty"`
+	Loadbefore           []string `protobuf:"bytes,5,rep,name=loadbefore,proto3" json:"loadbefore,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -583,6 +651,257 @@ func (m *PluginMetadata) GetConflicts() []string {
 	return nil
 }
 
+func (m *PluginMetadata) GetDepend() []string {
+	if m != nil {
+		return m.Depend
+	}
+	return nil
+}
+
+func (m *PluginMetadata) GetSoftdepend() []string {
+	if m != nil {
+		return m.Softdepend
+	}
+	return nil
+}
+
+func (m *PluginMetadata) GetLoadbefore() []string {
+	if m != nil {
+		return m.Loadbefore
+	}
+	return nil
+}
+
+type Deprecation struct {
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// replacement is the name of the plugin to use instead
+	Replacement          string   `protobuf:"bytes,2,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Deprecation) Reset()         { *m = Deprecation{} }
+func (m *Deprecation) String() string { return proto.CompactTextString(m) }
+func (*Deprecation) ProtoMessage()    {}
+func (*Deprecation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1b40cafcd4234784, []int{6}
+}
+func (m *Deprecation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Deprecation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Deprecation.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Deprecation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Deprecation.Merge(m, src)
+}
+func (m *Deprecation) XXX_Size() int {
+	return m.Size()
+}
+func (m *Deprecation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Deprecation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Deprecation proto.InternalMessageInfo
+
+func (m *Deprecation) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Deprecation) GetReplacement() string {
+	if m != nil {
+		return m.Replacement
+	}
+	return ""
+}
+
+// Release is a version of a plugin as it was uploaded
+type Release struct {
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PluginId string `protobuf:"bytes,2,opt,name=pluginId,proto3" json:"pluginId,omitempty"`
+	Version  string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// size of the jar in bytes
+	Size_ int64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	// sha256 of the jar, hex encoded
+	Sha256     string `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	UploadedAt int64  `protobuf:"varint,6,opt,name=uploadedAt,proto3" json:"uploadedAt,omitempty"`
+	Channel    string `protobuf:"bytes,7,opt,name=channel,proto3" json:"channel,omitempty"`
+	Uploader   *User  `protobuf:"bytes,8,opt,name=uploader,proto3" json:"uploader,omitempty"`
+	// minecraftVersion is the api-version of the plugin.yml, the oldest
+	// Minecraft version that the release runs on
+	MinecraftVersion string `protobuf:"bytes,9,opt,name=minecraftVersion,proto3" json:"minecraftVersion,omitempty"`
+	// metadata is what the plugin.yml of the release declares, downloads are
+	// only counted for the plugin. Releases recorded before it was kept have
+	// none
+	Metadata             *PluginMetadata `protobuf:"bytes,10,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Release) Reset()         { *m = Release{} }
+func (m *Release) String() string { return proto.CompactTextString(m) }
+func (*Release) ProtoMessage()    {}
+func (*Release) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1b40cafcd4234784, []int{7}
+}
+func (m *Release) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Release) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Release.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Release) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Release.Merge(m, src)
+}
+func (m *Release) XXX_Size() int {
+	return m.Size()
+}
+func (m *Release) XXX_DiscardUnknown() {
+	xxx_messageInfo_Release.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Release proto.InternalMessageInfo
+
+func (m *Release) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Release) GetPluginId() string {
+	if m != nil {
+		return m.PluginId
+	}
+	return ""
+}
+
+func (m *Release) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Release) GetSize_() int64 {
+	if m != nil {
+		return m.Size_
+	}
+	return 0
+}
+
+func (m *Release) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
+}
+
+func (m *Release) GetUploadedAt() int64 {
+	if m != nil {
+		return m.UploadedAt
+	}
+	return 0
+}
+
+func (m *Release) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *Release) GetUploader() *User {
+	if m != nil {
+		return m.Uploader
+	}
+	return nil
+}
+
+func (m *Release) GetMinecraftVersion() string {
+	if m != nil {
+		return m.MinecraftVersion
+	}
+	return ""
+}
+
+func (m *Release) GetMetadata() *PluginMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type Releases struct {
+	Releases             []*Release `protobuf:"bytes,1,rep,name=releases,proto3" json:"releases,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Releases) Reset()         { *m = Releases{} }
+func (m *Releases) String() string { return proto.CompactTextString(m) }
+func (*Releases) ProtoMessage()    {}
+func (*Releases) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1b40cafcd4234784, []int{8}
+}
+func (m *Releases) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Releases) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Releases.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Releases) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Releases.Merge(m, src)
+}
+func (m *Releases) XXX_Size() int {
+	return m.Size()
+}
+func (m *Releases) XXX_DiscardUnknown() {
+	xxx_messageInfo_Releases.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Releases proto.InternalMessageInfo
+
+func (m *Releases) GetReleases() []*Release {
+	if m != nil {
+		return m.Releases
+	}
+	return nil
+}
+
 type Premium struct {
 	Price                int32    `protobuf:"varint,1,opt,name=price,proto3" json:"price,omitempty"`
 	Purchases            int32    `protobuf:"varint,2,opt,name=purchases,proto3" json:"purchases,omitempty"`
@@ -595,7 +914,7 @@ func (m *Premium) Reset()         { *m = Premium{} }
 func (m *Premium) String() string { return proto.CompactTextString(m) }
 func (*Premium) ProtoMessage()    {}
 func (*Premium) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{6}
+	return fileDescriptor_1b40cafcd4234784, []int{9}
 }
 func (m *Premium) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -651,7 +970,7 @@ func (m *Readme) Reset()         { *m = Readme{} }
 func (m *Readme) String() string { return proto.CompactTextString(m) }
 func (*Readme) ProtoMessage()    {}
 func (*Readme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{7}
+	return fileDescriptor_1b40cafcd4234784, []int{10}
 }
 func (m *Readme) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -702,9 +1021,11 @@ func (m *Readme) GetText() string {
 }
 
 type Session struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId               string   `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"`
-	LastRetrieved        int64    `protobuf:"varint,3,opt,name=lastRetrieved,proto3" json:"lastRetrieved,omitempty"`
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	LastRetrieved int64  `protobuf:"varint,3,opt,name=lastRetrieved,proto3" json:"lastRetrieved,omitempty"`
+	// expiresAt is the unix time a session ends, a day after it starts when unset
+	ExpiresAt            int64    `protobuf:"varint,4,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -714,7 +1035,7 @@ func (m *Session) Reset()         { *m = Session{} }
 func (m *Session) String() string { return proto.CompactTextString(m) }
 func (*Session) ProtoMessage()    {}
 func (*Session) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{8}
+	return fileDescriptor_1b40cafcd4234784, []int{11}
 }
 func (m *Session) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -764,6 +1085,13 @@ func (m *Session) GetLastRetrieved() int64 {
 	return 0
 }
 
+func (m *Session) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
 type SessionInsertResponse struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -775,7 +1103,7 @@ func (m *SessionInsertResponse) Reset()         { *m = SessionInsertResponse{} }
 func (m *SessionInsertResponse) String() string { return proto.CompactTextString(m) }
 func (*SessionInsertResponse) ProtoMessage()    {}
 func (*SessionInsertResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{9}
+	return fileDescriptor_1b40cafcd4234784, []int{12}
 }
 func (m *SessionInsertResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -818,6 +1146,7 @@ type Changelog struct {
 	Added                []string `protobuf:"bytes,4,rep,name=added,proto3" json:"added,omitempty"`
 	Removed              []string `protobuf:"bytes,5,rep,name=removed,proto3" json:"removed,omitempty"`
 	Updated              []string `protobuf:"bytes,6,rep,name=updated,proto3" json:"updated,omitempty"`
+	Channel              string   `protobuf:"bytes,7,opt,name=channel,proto3" json:"channel,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -827,7 +1156,7 @@ func (m *Changelog) Reset()         { *m = Changelog{} }
 func (m *Changelog) String() string { return proto.CompactTextString(m) }
 func (*Changelog) ProtoMessage()    {}
 func (*Changelog) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{10}
+	return fileDescriptor_1b40cafcd4234784, []int{13}
 }
 func (m *Changelog) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -898,6 +1227,13 @@ func (m *Changelog) GetUpdated() []string {
 	return nil
 }
 
+func (m *Changelog) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
 type Changelogs struct {
 	Changelogs           []*Changelog `protobuf:"bytes,1,rep,name=changelogs,proto3" json:"changelogs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
@@ -909,7 +1245,7 @@ func (m *Changelogs) Reset()         { *m = Changelogs{} }
 func (m *Changelogs) String() string { return proto.CompactTextString(m) }
 func (*Changelogs) ProtoMessage()    {}
 func (*Changelogs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{11}
+	return fileDescriptor_1b40cafcd4234784, []int{14}
 }
 func (m *Changelogs) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -955,7 +1291,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1b40cafcd4234784, []int{12}
+	return fileDescriptor_1b40cafcd4234784, []int{15}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -985,6 +1321,7 @@ func (m *Empty) XXX_DiscardUnknown() {
 var xxx_messageInfo_Empty proto.InternalMessageInfo
 
 func init() {
+	proto.RegisterEnum("api.ArtifactType", ArtifactType_name, ArtifactType_value)
 	proto.RegisterEnum("api.Category", Category_name, Category_value)
 	proto.RegisterEnum("api.Sort", Sort_name, Sort_value)
 	proto.RegisterType((*User)(nil), "api.User")
@@ -992,7 +1329,12 @@ func init() {
 	proto.RegisterType((*PaginatePluginsRequest)(nil), "api.PaginatePluginsRequest")
 	proto.RegisterType((*PaginatePluginsResponse)(nil), "api.PaginatePluginsResponse")
 	proto.RegisterType((*Plugin)(nil), "api.Plugin")
+	proto.RegisterMapType((map[string]string)(nil), "api.Plugin.ChannelsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "api.Plugin.YankedEntry")
 	proto.RegisterType((*PluginMetadata)(nil), "api.PluginMetadata")
+	proto.RegisterType((*Deprecation)(nil), "api.Deprecation")
+	proto.RegisterType((*Release)(nil), "api.Release")
+	proto.RegisterType((*Releases)(nil), "api.Releases")
 	proto.RegisterType((*Premium)(nil), "api.Premium")
 	proto.RegisterType((*Readme)(nil), "api.Readme")
 	proto.RegisterType((*Session)(nil), "api.Session")
@@ -1005,76 +1347,102 @@ func init() {
 func init() { proto.RegisterFile("api/api.proto", fileDescriptor_1b40cafcd4234784) }
 
 var fileDescriptor_1b40cafcd4234784 = []byte{
-	// 1101 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x56, 0xdd, 0x6e, 0xe3, 0x44,
-	0x14, 0x8e, 0xe3, 0xc4, 0x89, 0x4f, 0xb6, 0xc5, 0x1a, 0x76, 0x8b, 0x15, 0x76, 0xab, 0xae, 0xbb,
-	0xec, 0x96, 0x5d, 0xa9, 0x2b, 0x05, 0xae, 0x10, 0x48, 0xa4, 0x69, 0x68, 0x23, 0xe5, 0xa7, 0x4c,
-	0x1a, 0xad, 0x40, 0xdc, 0x4c, 0xed, 0x21, 0x35, 0x4a, 0x6c, 0xe3, 0x99, 0x74, 0x59, 0x89, 0x27,
-	0xe0, 0x0a, 0xee, 0x90, 0xb8, 0xe5, 0x59, 0x10, 0x77, 0xf0, 0x08, 0xa8, 0x3c, 0x03, 0xf7, 0x68,
-	0x7e, 0xec, 0xd8, 0x69, 0x77, 0xf7, 0x6e, 0xbe, 0x73, 0xbe, 0x99, 0xf3, 0xe3, 0xef, 0x9c, 0x04,
-	0xb6, 0x48, 0x12, 0x3e, 0x27, 0x49, 0x78, 0x98, 0xa4, 0x31, 0x8f, 0x91, 0x49, 0x92, 0xd0, 0xfb,
-	0xcf, 0x80, 0xda, 0x8c, 0xd1, 0x14, 0x6d, 0x43, 0x35, 0x0c, 0x5c, 0x63, 0xcf, 0x38, 0xb0, 0x71,
-	0x35, 0x0c, 0x50, 0x1b, 0x9a, 0x2b, 0x46, 0xd3, 0x88, 0x2c, 0xa9, 0x5b, 0x95, 0xd6, 0x1c, 0xa3,
-	0xbb, 0x50, 0xa7, 0x4b, 0x12, 0x2e, 0x5c, 0x53, 0x3a, 0x14, 0x10, 0x37, 0x12, 0xc2, 0xd8, 0xcb,
-	0x38, 0x0d, 0xdc, 0x9a, 0xba, 0x91, 0x61, 0xb4, 0x03, 0x16, 0xf3, 0xe3, 0x84, 0x32, 0xb7, 0xbe,
-	0x67, 0x1e, 0xd8, 0x58, 0x23, 0xe4, 0x80, 0xc9, 0xc9, 0xdc, 0xb5, 0x24, 0x5d, 0x1c, 0xd1, 0x7d,
-	0xb0, 0xf9, 0xe5, 0x6a, 0x79, 0x11, 0x89, 0xf7, 0x1b, 0xd2, 0xbe, 0x36, 0x88, 0x18, 0x8c, 0xa7,
-	0x61, 0x42, 0x07, 0x81, 0xdb, 0x54, 0x31, 0x32, 0x8c, 0x9e, 0x81, 0x9d, 0xac, 0x52, 0xff, 0x92,
-	0x30, 0xca, 0x5c, 0x7b, 0xcf, 0x3c, 0x68, 0x75, 0xb6, 0x0e, 0x45, 0xb9, 0x67, 0xda, 0x8a, 0xd7,
-	0x7e, 0xef, 0x1b, 0x68, 0x66, 0x66, 0xf1, 0x68, 0x7c, 0xf1, 0x1d, 0xf5, 0xf9, 0x20, 0x6b, 0x40,
-	0x8e, 0x91, 0x0b, 0x0d, 0x46, 0x19, 0x0b, 0xe3, 0x48, 0x77, 0x21, 0x83, 0xe2, 0x96, 0x1f, 0x2f,
-	0x93, 0x05, 0xe5, 0x54, 0xf6, 0xa1, 0x89, 0x73, 0xec, 0xfd, 0x6e, 0xc0, 0xce, 0x19, 0x99, 0x87,
-	0x11, 0xe1, 0xf4, 0x6c, 0xb1, 0x9a, 0x87, 0x11, 0xc3, 0xf4, 0xfb, 0x15, 0x65, 0x1c, 0x21, 0xa8,
-	0x25, 0x64, 0x4e, 0x65, 0xa0, 0x3a, 0x96, 0x67, 0xd1, 0x4f, 0x3f, 0x5e, 0x45, 0x5c, 0x86, 0xa8,
-	0x63, 0x05, 0x64, 0xcf, 0x28, 0x49, 0xfd, 0x4b, 0xdd, 0x66, 0x8d, 0xd0, 0x87, 0xd0, 0xf4, 0x09,
-	0xa7, 0xf3, 0x38, 0x7d, 0x25, 0xfb, 0xbc, 0xad, 0xcb, 0xec, 0x69, 0x23, 0xce, 0xdd, 0xe8, 0x01,
-	0xd4, 0x58, 0x9c, 0x72, 0xb7, 0x2e, 0x69, 0xb6, 0xa4, 0x4d, 0xe3, 0x94, 0x63, 0x69, 0xf6, 0x3e,
-	0x87, 0xf7, 0x6e, 0x64, 0xc9, 0x92, 0x38, 0x62, 0x14, 0x7d, 0x00, 0x8d, 0x44, 0x99, 0x5c, 0x43,
-	0xb6, 0xb2, 0xa5, 0x5a, 0x29, 0x6d, 0x38, 0xf3, 0x79, 0x7f, 0x55, 0xc1, 0x52, 0xb6, 0x1b, 0x02,
-	0x42, 0x50, 0x2b, 0x88, 0x47, 0x9e, 0xd1, 0x43, 0xb0, 0xc8, 0x8a, 0x5f, 0xc6, 0xa9, 0x2c, 0xa9,
-	0xa5, 0x33, 0x12, 0xfa, 0xc3, 0xda, 0x21, 0x1a, 0x7e, 0x45, 0x53, 0xd9, 0x70, 0x25, 0xa2, 0x0c,
-	0xa2, 0x3d, 0x68, 0x05, 0x94, 0xf9, 0x69, 0x98, 0x70, 0xe1, 0xad, 0x4b, 0x6f, 0xd1, 0x54, 0xd6,
-	0x8e, 0xb5, 0xa9, 0x9d, 0x62, 0xdf, 0x1a, 0x6f, 0xee, 0xdb, 0x73, 0x68, 0x2e, 0x29, 0x27, 0x01,
-	0xe1, 0x44, 0xca, 0xac, 0xd5, 0x79, 0xb7, 0x50, 0xfe, 0x48, 0xbb, 0x70, 0x4e, 0x42, 0x8f, 0xa1,
-	0x91, 0xa4, 0x74, 0x19, 0xae, 0x96, 0xae, 0x2d, 0xf9, 0x77, 0x14, 0x5f, 0xd9, 0x70, 0xe6, 0x14,
-	0x35, 0x2c, 0x08, 0xe3, 0xb3, 0x24, 0x20, 0x9c, 0x06, 0x2e, 0xec, 0x19, 0x07, 0x26, 0x2e, 0x9a,
-	0xbc, 0x21, 0x6c, 0x97, 0xa3, 0x88, 0xaa, 0x82, 0xf8, 0x65, 0xb4, 0x88, 0x49, 0xc0, 0x64, 0x7f,
-	0x4d, 0xbc, 0x36, 0x08, 0xaf, 0x1f, 0x47, 0xdf, 0x2e, 0x42, 0x9f, 0x33, 0xb7, 0x2a, 0x87, 0x6b,
-	0x6d, 0xf0, 0x3e, 0x83, 0x86, 0xce, 0x41, 0x88, 0x2c, 0x49, 0x43, 0x3f, 0x53, 0x9e, 0x02, 0xe2,
-	0xfa, 0x7a, 0x68, 0x94, 0xfc, 0x0a, 0x53, 0xf2, 0x25, 0x58, 0x98, 0x92, 0x60, 0x49, 0x6f, 0x7c,
-	0xdd, 0x7d, 0xb0, 0x94, 0x06, 0xe4, 0xa5, 0x0d, 0x79, 0x68, 0x97, 0x90, 0x00, 0xa7, 0x3f, 0x70,
-	0xad, 0x5f, 0x79, 0xf6, 0x5e, 0x40, 0x63, 0xaa, 0x27, 0x68, 0xf3, 0xcd, 0x1d, 0xb0, 0xc4, 0x8a,
-	0x19, 0x04, 0x5a, 0x33, 0x1a, 0xa1, 0x47, 0xb0, 0x25, 0x3a, 0x84, 0x29, 0x4f, 0x43, 0x7a, 0x45,
-	0x03, 0xf9, 0x9e, 0x89, 0xcb, 0x46, 0xef, 0x09, 0xdc, 0xd3, 0x0f, 0x0f, 0x22, 0x46, 0x53, 0x9e,
-	0x4b, 0x79, 0x23, 0x8c, 0xf7, 0x9b, 0x01, 0x76, 0xef, 0x92, 0x44, 0x73, 0xba, 0x88, 0xe7, 0xb7,
-	0xed, 0x3d, 0x95, 0x7d, 0x9e, 0x46, 0x8e, 0x8b, 0xda, 0x34, 0xcb, 0xda, 0xbc, 0x0b, 0x75, 0x12,
-	0x04, 0x54, 0x2c, 0x3e, 0xf1, 0x05, 0x14, 0x10, 0xfc, 0x94, 0x2e, 0x63, 0x91, 0xb2, 0x5a, 0x7b,
-	0x19, 0x14, 0x9e, 0x95, 0xd6, 0x80, 0xa5, 0x3c, 0x1a, 0x7a, 0x9f, 0x02, 0xe4, 0xc9, 0x31, 0x74,
-	0x08, 0xe0, 0xe7, 0x48, 0x4f, 0xe2, 0xb6, 0x52, 0x6d, 0x66, 0xc6, 0x05, 0x86, 0xd7, 0x80, 0x7a,
-	0x7f, 0x99, 0xf0, 0x57, 0x4f, 0x7f, 0x32, 0xa0, 0x99, 0x09, 0x1b, 0x35, 0xc0, 0xec, 0x0e, 0x87,
-	0x4e, 0x05, 0xb5, 0xa0, 0x71, 0x86, 0xfb, 0xa3, 0xc1, 0x6c, 0xe4, 0x18, 0xc8, 0x86, 0xfa, 0xf9,
-	0x64, 0x32, 0x9c, 0x3a, 0x55, 0x61, 0xef, 0xf7, 0x26, 0xe3, 0xc9, 0xe8, 0x2b, 0xc7, 0x44, 0x4d,
-	0xa8, 0xf5, 0x4e, 0xbb, 0xe7, 0x4e, 0x0d, 0x6d, 0x81, 0x3d, 0xea, 0xf7, 0x4e, 0xbb, 0xe3, 0x41,
-	0x6f, 0xea, 0xd4, 0xc5, 0x85, 0xee, 0xf1, 0x68, 0x30, 0x76, 0x2c, 0x04, 0x60, 0x1d, 0xcd, 0xc6,
-	0x27, 0xfd, 0xbe, 0xd3, 0x10, 0xaf, 0x7f, 0x31, 0x1b, 0x3b, 0x4d, 0x71, 0x71, 0x34, 0x98, 0xf6,
-	0x1c, 0x5b, 0x5c, 0x1c, 0x0e, 0x8e, 0x70, 0x17, 0x0f, 0xfa, 0x53, 0x07, 0x9e, 0x7e, 0x02, 0x35,
-	0xb1, 0x75, 0x04, 0x61, 0x3c, 0x19, 0xf7, 0x9d, 0x8a, 0x20, 0x1c, 0x4f, 0x5e, 0x8c, 0x87, 0x93,
-	0xee, 0xf1, 0xd4, 0x31, 0x04, 0x3c, 0x9b, 0xe1, 0xde, 0x69, 0x77, 0xda, 0x17, 0xe9, 0x00, 0x58,
-	0xc3, 0xee, 0x79, 0x7f, 0x7a, 0xee, 0x98, 0x1d, 0x06, 0x77, 0xc4, 0x7e, 0x60, 0x53, 0x9a, 0x5e,
-	0x09, 0xc1, 0x3e, 0x00, 0xf3, 0x84, 0x72, 0xb4, 0xde, 0x1c, 0xed, 0xf5, 0xd1, 0xab, 0x88, 0x0d,
-	0xa3, 0x3e, 0x7f, 0x91, 0x01, 0xf2, 0x28, 0x1b, 0xa3, 0x28, 0x6a, 0xd8, 0x5e, 0x4b, 0xe9, 0xfc,
-	0x61, 0x64, 0x53, 0x98, 0xc7, 0x7d, 0xa8, 0xe2, 0x16, 0x75, 0xde, 0x2e, 0x02, 0xaf, 0x22, 0x66,
-	0x42, 0xc7, 0x2e, 0xb1, 0xca, 0xd1, 0xf7, 0xf3, 0xe8, 0x6f, 0x20, 0x9d, 0x40, 0x33, 0x5b, 0xcc,
-	0xe8, 0x7d, 0x45, 0xbb, 0xf5, 0xd7, 0xa4, 0x7d, 0xff, 0x76, 0xa7, 0x52, 0xbe, 0x57, 0xe9, 0xfc,
-	0x08, 0x5b, 0x6a, 0x80, 0xdf, 0x5e, 0x86, 0xe2, 0xdd, 0x52, 0x86, 0x72, 0xbc, 0xa5, 0x8c, 0xdb,
-	0x48, 0x9d, 0x5f, 0x0c, 0xd8, 0xd6, 0x33, 0x99, 0xc5, 0xdf, 0x57, 0xf1, 0xd5, 0x7a, 0xd4, 0xbe,
-	0x76, 0x09, 0x79, 0x15, 0xf4, 0x71, 0x9e, 0x41, 0x99, 0xd7, 0x2e, 0xa2, 0xf2, 0x94, 0x7b, 0x15,
-	0xf4, 0x08, 0xac, 0x63, 0x2a, 0x7e, 0x7e, 0x37, 0x6e, 0x95, 0x73, 0xfa, 0xd9, 0x00, 0x27, 0x9f,
-	0x9d, 0x2c, 0xab, 0x27, 0x2a, 0xab, 0x8d, 0xc9, 0x6a, 0x6f, 0x60, 0xaf, 0x82, 0x1e, 0xe7, 0x99,
-	0x6d, 0x72, 0xcb, 0xed, 0x79, 0x06, 0xd6, 0x09, 0xe5, 0xdd, 0xc5, 0xe2, 0x06, 0xef, 0x9d, 0x32,
-	0x66, 0x5e, 0xe5, 0xe8, 0xde, 0x9f, 0xd7, 0xbb, 0xc6, 0xdf, 0xd7, 0xbb, 0xc6, 0x3f, 0xd7, 0xbb,
-	0xc6, 0xaf, 0xff, 0xee, 0x56, 0xbe, 0x16, 0x7f, 0xcd, 0x2e, 0x2c, 0xf9, 0x37, 0xed, 0xa3, 0xff,
-	0x03, 0x00, 0x00, 0xff, 0xff, 0x48, 0x79, 0x9c, 0x34, 0xb7, 0x09, 0x00, 0x00,
+	// 1509 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xdd, 0x6e, 0xdb, 0xc8,
+	0x15, 0x16, 0x4d, 0x89, 0x92, 0x8e, 0x2c, 0x87, 0x99, 0xfc, 0xb1, 0x6a, 0x62, 0x38, 0x74, 0x7e,
+	0x1c, 0xa7, 0xb0, 0x01, 0x35, 0x29, 0xda, 0xb4, 0x05, 0x2a, 0xcb, 0x8a, 0x23, 0xc0, 0x96, 0xdc,
+	0x91, 0x9d, 0x20, 0xbd, 0x29, 0xc6, 0xe4, 0xd8, 0x66, 0x23, 0x91, 0x2c, 0x67, 0xe4, 0xc4, 0x45,
+	0x9f, 0xa0, 0x57, 0xed, 0x5d, 0x1f, 0x20, 0x37, 0xfb, 0x00, 0x7b, 0xb1, 0x6f, 0xb0, 0x97, 0xfb,
+	0x08, 0x8b, 0xec, 0x33, 0xec, 0xfd, 0x62, 0x7e, 0x48, 0x91, 0xb2, 0xf3, 0xb3, 0xc0, 0xde, 0xcd,
+	0x77, 0xce, 0x37, 0x73, 0x7e, 0xe6, 0x9c, 0x33, 0x24, 0x34, 0x49, 0x1c, 0x6c, 0x92, 0x38, 0xd8,
+	0x88, 0x93, 0x88, 0x47, 0xc8, 0x24, 0x71, 0xe0, 0xfe, 0x68, 0x40, 0xf9, 0x90, 0xd1, 0x04, 0x2d,
+	0xc1, 0x42, 0xe0, 0x3b, 0xc6, 0x8a, 0xb1, 0x56, 0xc7, 0x0b, 0x81, 0x8f, 0x5a, 0x50, 0x9b, 0x32,
+	0x9a, 0x84, 0x64, 0x42, 0x9d, 0x05, 0x29, 0xcd, 0x30, 0xba, 0x0e, 0x15, 0x3a, 0x21, 0xc1, 0xd8,
+	0x31, 0xa5, 0x42, 0x01, 0xb1, 0x23, 0x26, 0x8c, 0xbd, 0x8d, 0x12, 0xdf, 0x29, 0xab, 0x1d, 0x29,
+	0x46, 0x37, 0xc1, 0x62, 0x5e, 0x14, 0x53, 0xe6, 0x54, 0x56, 0xcc, 0xb5, 0x3a, 0xd6, 0x08, 0xd9,
+	0x60, 0x72, 0x72, 0xe2, 0x58, 0x92, 0x2e, 0x96, 0xe8, 0x36, 0xd4, 0xf9, 0xe9, 0x74, 0x72, 0x14,
+	0x8a, 0xf3, 0xab, 0x52, 0x3e, 0x13, 0x08, 0x1b, 0x8c, 0x27, 0x41, 0x4c, 0xfb, 0xbe, 0x53, 0x53,
+	0x36, 0x52, 0x8c, 0x1e, 0x43, 0x3d, 0x9e, 0x26, 0xde, 0x29, 0x61, 0x94, 0x39, 0xf5, 0x15, 0x73,
+	0xad, 0xd1, 0x6e, 0x6e, 0x88, 0x70, 0xf7, 0xb5, 0x14, 0xcf, 0xf4, 0xee, 0x16, 0xd4, 0x52, 0xb1,
+	0x38, 0x34, 0x3a, 0xfa, 0x07, 0xf5, 0x78, 0x3f, 0x4d, 0x40, 0x86, 0x85, 0xce, 0x8b, 0x26, 0xf1,
+	0x98, 0x72, 0x2a, 0xa3, 0xad, 0xe1, 0x0c, 0xbb, 0xef, 0x0d, 0xb8, 0xb9, 0x4f, 0x4e, 0x82, 0x90,
+	0x70, 0xba, 0x3f, 0x9e, 0x9e, 0x04, 0x21, 0xc3, 0xf4, 0x9f, 0x53, 0xca, 0x38, 0x42, 0x50, 0x8e,
+	0xc9, 0x09, 0x95, 0xc7, 0x55, 0xb0, 0x5c, 0x8b, 0xac, 0x79, 0xd1, 0x34, 0xe4, 0x32, 0x9d, 0x15,
+	0xac, 0x80, 0xcc, 0x0c, 0x25, 0x89, 0x77, 0xaa, 0x93, 0xa9, 0x11, 0x7a, 0x04, 0x35, 0x8f, 0x70,
+	0x7a, 0x12, 0x25, 0xe7, 0x32, 0x9b, 0x4b, 0x3a, 0x98, 0xae, 0x16, 0xe2, 0x4c, 0x8d, 0xee, 0x40,
+	0x99, 0x45, 0x09, 0x77, 0x2a, 0x92, 0x56, 0x97, 0xb4, 0x51, 0x94, 0x70, 0x2c, 0xc5, 0xee, 0x5f,
+	0xe0, 0xd6, 0x05, 0x2f, 0x59, 0x1c, 0x85, 0x8c, 0xa2, 0xfb, 0x50, 0x8d, 0x95, 0xc8, 0x31, 0x64,
+	0xc2, 0x1a, 0x2a, 0x61, 0x52, 0x86, 0x53, 0x9d, 0xfb, 0xbe, 0x02, 0x96, 0x92, 0x5d, 0x28, 0x13,
+	0x04, 0xe5, 0x5c, 0x89, 0xc8, 0x35, 0xba, 0x0b, 0x16, 0x99, 0xf2, 0xd3, 0x28, 0x91, 0x21, 0x35,
+	0xb4, 0x47, 0xa2, 0xca, 0xb0, 0x56, 0x20, 0x07, 0xaa, 0x67, 0x34, 0x61, 0x41, 0x14, 0xea, 0x52,
+	0x49, 0x21, 0x5a, 0x81, 0x86, 0x4f, 0x99, 0x97, 0x04, 0x31, 0x17, 0xda, 0x8a, 0xd4, 0xe6, 0x45,
+	0xc5, 0x0a, 0xb1, 0xe6, 0x2b, 0x24, 0x9f, 0xb7, 0xea, 0xa7, 0xf3, 0xb6, 0x09, 0xb5, 0x09, 0xe5,
+	0xc4, 0x27, 0x9c, 0xc8, 0x62, 0x6a, 0xb4, 0xaf, 0xe5, 0xc2, 0xdf, 0xd3, 0x2a, 0x9c, 0x91, 0xd0,
+	0x03, 0xa8, 0xc6, 0x09, 0x9d, 0x04, 0xd3, 0x89, 0x53, 0x97, 0xfc, 0x45, 0xc5, 0x57, 0x32, 0x9c,
+	0x2a, 0x45, 0x0c, 0x63, 0xc2, 0xf8, 0x61, 0xec, 0x13, 0x4e, 0x7d, 0x07, 0x56, 0x8c, 0x35, 0x13,
+	0xe7, 0x45, 0xe8, 0x3e, 0x94, 0xf9, 0x79, 0x4c, 0x9d, 0x86, 0xf4, 0xf0, 0xaa, 0x3c, 0xa6, 0x93,
+	0xf0, 0xe0, 0x98, 0x78, 0xfc, 0xe0, 0x3c, 0xa6, 0x58, 0xaa, 0x45, 0x9a, 0xbc, 0x53, 0x12, 0x86,
+	0x74, 0xec, 0x2c, 0xaa, 0x34, 0x69, 0x88, 0x9e, 0x42, 0x4d, 0x2f, 0x99, 0xd3, 0x94, 0x57, 0xf7,
+	0xab, 0x9c, 0xef, 0x1b, 0x5d, 0xad, 0xeb, 0x85, 0x5c, 0x86, 0xac, 0x21, 0xda, 0x04, 0xeb, 0x9c,
+	0x84, 0x6f, 0xa8, 0xef, 0x2c, 0xc9, 0x4d, 0xb7, 0xf2, 0x9b, 0x5e, 0x4b, 0x8d, 0xda, 0xa2, 0x69,
+	0xa8, 0x2d, 0xae, 0x23, 0x4e, 0xa8, 0x47, 0xe4, 0x75, 0x5c, 0x91, 0x61, 0xdb, 0x72, 0xd7, 0xf6,
+	0x4c, 0x8e, 0xf3, 0xa4, 0xd6, 0x1f, 0xa1, 0x59, 0xb0, 0x2f, 0xba, 0xfc, 0x0d, 0x3d, 0xd7, 0x55,
+	0x23, 0x96, 0xa2, 0x17, 0xce, 0xc8, 0x78, 0x9a, 0xd6, 0x8d, 0x02, 0xcf, 0x16, 0x7e, 0x6f, 0xb4,
+	0xfe, 0x00, 0x8d, 0x9c, 0x1f, 0x3f, 0x67, 0xab, 0xe8, 0xc7, 0xa5, 0xe2, 0xdd, 0x89, 0x5a, 0xf1,
+	0xa3, 0xb7, 0xe1, 0x38, 0x22, 0x3e, 0x93, 0x87, 0x98, 0x78, 0x26, 0x10, 0x5a, 0x2f, 0x0a, 0x8f,
+	0xc7, 0x81, 0xc7, 0x99, 0xb3, 0x20, 0x07, 0xd3, 0x4c, 0x20, 0x3a, 0xd3, 0xa7, 0x31, 0x0d, 0x7d,
+	0xc7, 0x54, 0x33, 0x4b, 0x21, 0xb4, 0x0c, 0xc0, 0xa2, 0x63, 0xae, 0x75, 0x65, 0xa9, 0xcb, 0x49,
+	0x84, 0x5e, 0x1c, 0x7f, 0x44, 0x8f, 0xa3, 0x84, 0xea, 0x79, 0x97, 0x93, 0xb8, 0x3b, 0xd0, 0xc8,
+	0xa5, 0x4e, 0x98, 0x49, 0x28, 0x61, 0x51, 0xa8, 0x83, 0xd4, 0x48, 0x14, 0x51, 0x42, 0xe3, 0x31,
+	0xf1, 0xe8, 0x84, 0xea, 0xa1, 0x51, 0xc7, 0x79, 0x91, 0xfb, 0xcd, 0x02, 0x54, 0x31, 0x1d, 0x53,
+	0x31, 0xc3, 0x2e, 0x19, 0xdf, 0xaa, 0x7b, 0xfb, 0x7e, 0x3a, 0xbe, 0x53, 0x9c, 0x6f, 0x3e, 0xb3,
+	0xd8, 0x7c, 0x08, 0xca, 0x2c, 0xf8, 0x17, 0x95, 0x3d, 0x69, 0x62, 0xb9, 0x96, 0x03, 0xea, 0x94,
+	0xb4, 0x9f, 0xfe, 0x4e, 0xf7, 0xa2, 0x46, 0x22, 0xcc, 0x69, 0x2c, 0xc2, 0xa2, 0x7e, 0x87, 0xcb,
+	0x3e, 0x34, 0x71, 0x4e, 0x92, 0xaf, 0xdd, 0x6a, 0xb1, 0x76, 0xef, 0x43, 0x4d, 0xf3, 0x12, 0xa7,
+	0x36, 0x3f, 0x21, 0x32, 0x15, 0x5a, 0x07, 0x7b, 0x12, 0x84, 0xd4, 0x4b, 0xc8, 0x31, 0x7f, 0xa9,
+	0xfd, 0xad, 0xcb, 0x93, 0x2e, 0xc8, 0x0b, 0xad, 0x0c, 0x5f, 0xd0, 0xca, 0xee, 0x13, 0xa8, 0xe9,
+	0xd4, 0x31, 0xb4, 0x06, 0xb5, 0x44, 0xaf, 0xf5, 0x18, 0x54, 0x7d, 0xad, 0x09, 0x38, 0xd3, 0xba,
+	0x7f, 0x86, 0xaa, 0x6e, 0x76, 0x51, 0x86, 0x71, 0x12, 0x78, 0xe9, 0x88, 0x57, 0x40, 0x54, 0xd4,
+	0xec, 0x0d, 0x52, 0x73, 0x7e, 0x26, 0x70, 0xff, 0x0a, 0x16, 0xa6, 0xc4, 0x9f, 0x5c, 0xbc, 0xae,
+	0x55, 0xb0, 0xd4, 0xf5, 0xc8, 0x4d, 0x73, 0x73, 0x58, 0xab, 0xc4, 0xed, 0x70, 0xfa, 0x8e, 0xeb,
+	0x4b, 0x93, 0x6b, 0x77, 0x0a, 0xd5, 0x11, 0x65, 0x32, 0x07, 0xf3, 0x67, 0xde, 0x04, 0x4b, 0xbc,
+	0xd8, 0x59, 0x01, 0x68, 0x84, 0xee, 0x41, 0x53, 0x8c, 0x22, 0x4c, 0x79, 0x12, 0xd0, 0x33, 0xea,
+	0xcb, 0xf3, 0x4c, 0x5c, 0x14, 0x8a, 0x48, 0xe8, 0xbb, 0x38, 0x48, 0x28, 0xeb, 0x70, 0x5d, 0x0f,
+	0x33, 0x81, 0xfb, 0x10, 0x6e, 0x68, 0xb3, 0xfd, 0x90, 0xd1, 0x84, 0x67, 0x2f, 0xca, 0x9c, 0x13,
+	0xee, 0xd7, 0x06, 0xd4, 0xc5, 0x30, 0x38, 0xa1, 0xe3, 0xe8, 0xe4, 0x17, 0xaa, 0xd2, 0xeb, 0x50,
+	0x21, 0xbe, 0x4f, 0xd3, 0xde, 0x53, 0x40, 0xf0, 0x13, 0x3a, 0x89, 0x44, 0x40, 0xaa, 0xe7, 0x52,
+	0x28, 0x34, 0x53, 0x3d, 0x8a, 0x2d, 0xa5, 0xd1, 0xf0, 0xe3, 0x35, 0xea, 0xfe, 0x09, 0x20, 0x73,
+	0x9b, 0xa1, 0x0d, 0x00, 0x2f, 0x43, 0xba, 0x46, 0x96, 0xd4, 0xb3, 0x92, 0x8a, 0x71, 0x8e, 0xe1,
+	0x56, 0xa1, 0xd2, 0x9b, 0xc4, 0xfc, 0x7c, 0x7d, 0x13, 0x16, 0xf3, 0x63, 0x1d, 0x01, 0x58, 0xfb,
+	0xbb, 0x87, 0x3b, 0xfd, 0x81, 0x5d, 0x42, 0xd7, 0xe0, 0xca, 0xa8, 0x87, 0x5f, 0xf6, 0xf0, 0xdf,
+	0x47, 0xc3, 0xe7, 0x07, 0xaf, 0x3a, 0xb8, 0x67, 0x1b, 0xeb, 0xff, 0x31, 0xa0, 0x96, 0x3e, 0x55,
+	0xa8, 0x0a, 0x66, 0x67, 0x77, 0xd7, 0x2e, 0xa1, 0x06, 0x54, 0xf7, 0x71, 0x6f, 0xaf, 0x7f, 0xb8,
+	0x67, 0x1b, 0xa8, 0x0e, 0x95, 0x83, 0xe1, 0x70, 0x77, 0x64, 0x2f, 0x08, 0x79, 0xaf, 0x3b, 0x1c,
+	0x0c, 0xf7, 0x5e, 0xdb, 0x26, 0xaa, 0x41, 0xb9, 0xfb, 0xa2, 0x73, 0x60, 0x97, 0x51, 0x13, 0xea,
+	0x7b, 0xbd, 0xee, 0x8b, 0xce, 0xa0, 0xdf, 0x1d, 0xd9, 0x15, 0xb1, 0xa1, 0xb3, 0xbd, 0xd7, 0x1f,
+	0xd8, 0x96, 0xb0, 0xbf, 0x75, 0x38, 0xd8, 0xe9, 0xf5, 0xec, 0xaa, 0x38, 0xfd, 0xf9, 0xe1, 0xc0,
+	0xae, 0x89, 0x8d, 0x7b, 0xfd, 0x51, 0xd7, 0xae, 0x8b, 0x8d, 0xbb, 0xfd, 0x2d, 0xdc, 0xc1, 0xfd,
+	0xde, 0xc8, 0x86, 0xf5, 0x67, 0x50, 0x16, 0xdf, 0x11, 0x82, 0x30, 0x18, 0x0e, 0x7a, 0x76, 0x49,
+	0x10, 0xb6, 0x87, 0xaf, 0x06, 0xbb, 0xc3, 0xce, 0xf6, 0xc8, 0x36, 0x04, 0xdc, 0x3f, 0xc4, 0xdd,
+	0x17, 0x9d, 0x51, 0x4f, 0xb8, 0x03, 0x60, 0xed, 0x76, 0x0e, 0x7a, 0xa3, 0x03, 0xdb, 0x6c, 0x33,
+	0x58, 0x14, 0xfd, 0xcc, 0x46, 0x34, 0x39, 0x13, 0x9d, 0x71, 0x07, 0xcc, 0x1d, 0xca, 0xd1, 0xac,
+	0xd3, 0x5b, 0xb3, 0xa5, 0x5b, 0x12, 0xdf, 0x0c, 0xaa, 0x92, 0xf2, 0x0c, 0x90, 0x4b, 0x99, 0x49,
+	0x45, 0x51, 0xcf, 0xe7, 0x47, 0x29, 0xed, 0xaf, 0xcc, 0xf4, 0x05, 0xc8, 0xec, 0xde, 0x55, 0x76,
+	0xf3, 0x0d, 0xd5, 0xca, 0x03, 0xb7, 0x84, 0x56, 0x33, 0xdb, 0x05, 0x56, 0xd1, 0xfa, 0x6a, 0x66,
+	0xfd, 0x13, 0xa4, 0x1d, 0xa8, 0xa5, 0x9f, 0x5a, 0xe8, 0xd7, 0x8a, 0x76, 0xe9, 0xf7, 0x61, 0xeb,
+	0xf6, 0xe5, 0x4a, 0xd5, 0x44, 0x6e, 0x09, 0x3d, 0x86, 0x66, 0xda, 0x58, 0x6a, 0xbe, 0x17, 0x26,
+	0xd2, 0x9c, 0xd5, 0xdf, 0x40, 0x63, 0x87, 0xf2, 0x6c, 0x9c, 0x15, 0xa9, 0xcd, 0x3c, 0x62, 0xea,
+	0xe8, 0x6d, 0x2a, 0xbe, 0x5f, 0xbf, 0xe4, 0xe8, 0x47, 0xb0, 0xa8, 0xa2, 0x56, 0x6f, 0xf2, 0xa7,
+	0x62, 0xdf, 0x80, 0xab, 0x8a, 0x9a, 0x7f, 0xdc, 0x3e, 0xce, 0x6f, 0xff, 0x1b, 0x9a, 0x6a, 0x18,
+	0x7e, 0xfe, 0xa6, 0x14, 0xef, 0x92, 0x9b, 0x52, 0x8a, 0xcf, 0xdc, 0xd4, 0x65, 0xa4, 0xf6, 0xff,
+	0x0c, 0x58, 0xd2, 0x13, 0x2c, 0xb5, 0xbf, 0xaa, 0xec, 0xab, 0x74, 0x68, 0x5d, 0xab, 0x80, 0xdc,
+	0x12, 0x7a, 0x92, 0x79, 0x50, 0xe4, 0xb5, 0xf2, 0xa8, 0x38, 0x13, 0xdd, 0x12, 0xba, 0x07, 0x96,
+	0xca, 0xf9, 0xdc, 0xae, 0xa2, 0x4f, 0xff, 0x35, 0xc0, 0xce, 0xe6, 0x49, 0xea, 0xd5, 0x43, 0xe5,
+	0xd5, 0xdc, 0xb4, 0x69, 0xcd, 0x61, 0xb7, 0x84, 0x1e, 0x64, 0x9e, 0xcd, 0x73, 0x8b, 0xe9, 0x79,
+	0x0c, 0xd6, 0x0e, 0xe5, 0x9d, 0xf1, 0xf8, 0x02, 0xef, 0x4a, 0x11, 0x33, 0xb7, 0xb4, 0x75, 0xe3,
+	0xdb, 0x0f, 0xcb, 0xc6, 0x77, 0x1f, 0x96, 0x8d, 0xef, 0x3f, 0x2c, 0x1b, 0xff, 0xff, 0x61, 0xb9,
+	0xf4, 0x37, 0xf1, 0xd7, 0x78, 0x64, 0xc9, 0x3f, 0xc8, 0xdf, 0xfe, 0x34, 0x00, 0x51, 0x02, 0x96,
+	0x9f, 0x52, 0x0e, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1237,6 +1605,13 @@ type PluginsServiceClient interface {
 	Insert(ctx context.Context, in *Plugin, opts ...grpc.CallOption) (*Empty, error)
 	Update(ctx context.Context, in *Plugin, opts ...grpc.CallOption) (*Empty, error)
 	Paginate(ctx context.Context, in *PaginatePluginsRequest, opts ...grpc.CallOption) (*PaginatePluginsResponse, error)
+	InsertRelease(ctx context.Context, in *Release, opts ...grpc.CallOption) (*Empty, error)
+	GetReleases(ctx context.Context, in *Release, opts ...grpc.CallOption) (*Releases, error)
+	DeleteRelease(ctx context.Context, in *Release, opts ...grpc.CallOption) (*Empty, error)
+	// UpdateYanked only changes the yanked versions of a plugin
+	UpdateYanked(ctx context.Context, in *Plugin, opts ...grpc.CallOption) (*Empty, error)
+	// UpdateDeprecation only changes the deprecation of a plugin
+	UpdateDeprecation(ctx context.Context, in *Plugin, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type pluginsServiceClient struct {
@@ -1283,23 +1658,75 @@ func (c *pluginsServiceClient) Paginate(ctx context.Context, in *PaginatePlugins
 	return out, nil
 }
 
-// PluginsServiceServer is the server API for PluginsService service.
-type PluginsServiceServer interface {
-	Get(context.Context, *Plugin) (*Plugin, error)
-	Insert(context.Context, *Plugin) (*Empty, error)
-	Update(context.Context, *Plugin) (*Empty, error)
-	Paginate(context.Context, *PaginatePluginsRequest) (*PaginatePluginsResponse, error)
+func (c *pluginsServiceClient) InsertRelease(ctx context.Context, in *Release, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/api.PluginsService/InsertRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedPluginsServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedPluginsServiceServer struct {
+func (c *pluginsServiceClient) GetReleases(ctx context.Context, in *Release, opts ...grpc.CallOption) (*Releases, error) {
+	out := new(Releases)
+	err := c.cc.Invoke(ctx, "/api.PluginsService/GetReleases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (*UnimplementedPluginsServiceServer) Get(ctx context.Context, req *Plugin) (*Plugin, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+func (c *pluginsServiceClient) DeleteRelease(ctx context.Context, in *Release, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/api.PluginsService/DeleteRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (*UnimplementedPluginsServiceServer) Insert(ctx context.Context, req *Plugin) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Insert not implemented")
+
+func (c *pluginsServiceClient) UpdateYanked(ctx context.Context, in *Plugin, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/api.PluginsService/UpdateYanked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginsServiceClient) UpdateDeprecation(ctx context.Context, in *Plugin, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/api.PluginsService/UpdateDeprecation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PluginsServiceServer is the server API for PluginsService service.
+type PluginsServiceServer interface {
+	Get(context.Context, *Plugin) (*Plugin, error)
+	Insert(context.Context, *Plugin) (*Empty, error)
+	Update(context.Context, *Plugin) (*Empty, error)
+	Paginate(context.Context, *PaginatePluginsRequest) (*PaginatePluginsResponse, error)
+	InsertRelease(context.Context, *Release) (*Empty, error)
+	GetReleases(context.Context, *Release) (*Releases, error)
+	DeleteRelease(context.Context, *Release) (*Empty, error)
+	// UpdateYanked only changes the yanked versions of a plugin
+	UpdateYanked(context.Context, *Plugin) (*Empty, error)
+	// UpdateDeprecation only changes the deprecation of a plugin
+	UpdateDeprecation(context.Context, *Plugin) (*Empty, error)
+}
+
+// UnimplementedPluginsServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPluginsServiceServer struct {
+}
+
+func (*UnimplementedPluginsServiceServer) Get(ctx context.Context, req *Plugin) (*Plugin, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedPluginsServiceServer) Insert(ctx context.Context, req *Plugin) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Insert not implemented")
 }
 func (*UnimplementedPluginsServiceServer) Update(ctx context.Context, req *Plugin) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
@@ -1307,6 +1734,21 @@ func (*UnimplementedPluginsServiceServer) Update(ctx context.Context, req *Plugi
 func (*UnimplementedPluginsServiceServer) Paginate(ctx context.Context, req *PaginatePluginsRequest) (*PaginatePluginsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Paginate not implemented")
 }
+func (*UnimplementedPluginsServiceServer) InsertRelease(ctx context.Context, req *Release) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertRelease not implemented")
+}
+func (*UnimplementedPluginsServiceServer) GetReleases(ctx context.Context, req *Release) (*Releases, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReleases not implemented")
+}
+func (*UnimplementedPluginsServiceServer) DeleteRelease(ctx context.Context, req *Release) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRelease not implemented")
+}
+func (*UnimplementedPluginsServiceServer) UpdateYanked(ctx context.Context, req *Plugin) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateYanked not implemented")
+}
+func (*UnimplementedPluginsServiceServer) UpdateDeprecation(ctx context.Context, req *Plugin) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDeprecation not implemented")
+}
 
 func RegisterPluginsServiceServer(s *grpc.Server, srv PluginsServiceServer) {
 	s.RegisterService(&_PluginsService_serviceDesc, srv)
@@ -1384,6 +1826,96 @@ func _PluginsService_Paginate_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PluginsService_InsertRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Release)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginsServiceServer).InsertRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.PluginsService/InsertRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginsServiceServer).InsertRelease(ctx, req.(*Release))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginsService_GetReleases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Release)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginsServiceServer).GetReleases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.PluginsService/GetReleases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginsServiceServer).GetReleases(ctx, req.(*Release))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginsService_DeleteRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Release)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginsServiceServer).DeleteRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.PluginsService/DeleteRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginsServiceServer).DeleteRelease(ctx, req.(*Release))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginsService_UpdateYanked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Plugin)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginsServiceServer).UpdateYanked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.PluginsService/UpdateYanked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginsServiceServer).UpdateYanked(ctx, req.(*Plugin))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginsService_UpdateDeprecation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Plugin)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginsServiceServer).UpdateDeprecation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.PluginsService/UpdateDeprecation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginsServiceServer).UpdateDeprecation(ctx, req.(*Plugin))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PluginsService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.PluginsService",
 	HandlerType: (*PluginsServiceServer)(nil),
@@ -1404,6 +1936,26 @@ var _PluginsService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Paginate",
 			Handler:    _PluginsService_Paginate_Handler,
 		},
+		{
+			MethodName: "InsertRelease",
+			Handler:    _PluginsService_InsertRelease_Handler,
+		},
+		{
+			MethodName: "GetReleases",
+			Handler:    _PluginsService_GetReleases_Handler,
+		},
+		{
+			MethodName: "DeleteRelease",
+			Handler:    _PluginsService_DeleteRelease_Handler,
+		},
+		{
+			MethodName: "UpdateYanked",
+			Handler:    _PluginsService_UpdateYanked_Handler,
+		},
+		{
+			MethodName: "UpdateDeprecation",
+			Handler:    _PluginsService_UpdateDeprecation_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/api.proto",
@@ -1974,13 +2526,6 @@ func (m *Purchase) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x18
 	}
-	if len(m.Session) > 0 {
-		i -= len(m.Session)
-		copy(dAtA[i:], m.Session)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.Session)))
-		i--
-		dAtA[i] = 0x12
-	}
 	if len(m.ObjectId) > 0 {
 		i -= len(m.ObjectId)
 		copy(dAtA[i:], m.ObjectId)
@@ -2110,6 +2655,68 @@ func (m *Plugin) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Deprecation != nil {
+		{
+			size, err := m.Deprecation.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x7a
+	}
+	if len(m.Yanked) > 0 {
+		for k := range m.Yanked {
+			v := m.Yanked[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintApi(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintApi(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintApi(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x72
+		}
+	}
+	if len(m.Channels) > 0 {
+		for k := range m.Channels {
+			v := m.Channels[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintApi(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintApi(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintApi(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x6a
+		}
+	}
+	if len(m.Channel) > 0 {
+		i -= len(m.Channel)
+		copy(dAtA[i:], m.Channel)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Channel)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.Type != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x58
+	}
 	if m.LastUpdated != 0 {
 		i = encodeVarintApi(dAtA, i, uint64(m.LastUpdated))
 		i--
@@ -2218,6 +2825,33 @@ func (m *PluginMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Loadbefore) > 0 {
+		for iNdEx := len(m.Loadbefore) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Loadbefore[iNdEx])
+			copy(dAtA[i:], m.Loadbefore[iNdEx])
+			i = encodeVarintApi(dAtA, i, uint64(len(m.Loadbefore[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.Softdepend) > 0 {
+		for iNdEx := len(m.Softdepend) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Softdepend[iNdEx])
+			copy(dAtA[i:], m.Softdepend[iNdEx])
+			i = encodeVarintApi(dAtA, i, uint64(len(m.Softdepend[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Depend) > 0 {
+		for iNdEx := len(m.Depend) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Depend[iNdEx])
+			copy(dAtA[i:], m.Depend[iNdEx])
+			i = encodeVarintApi(dAtA, i, uint64(len(m.Depend[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.Conflicts) > 0 {
 		for iNdEx := len(m.Conflicts) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.Conflicts[iNdEx])
@@ -2235,7 +2869,7 @@ func (m *PluginMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *Premium) Marshal() (dAtA []byte, err error) {
+func (m *Deprecation) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2245,12 +2879,12 @@ func (m *Premium) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Premium) MarshalTo(dAtA []byte) (int, error) {
+func (m *Deprecation) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Premium) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Deprecation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2259,20 +2893,24 @@ func (m *Premium) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.Purchases != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.Purchases))
+	if len(m.Replacement) > 0 {
+		i -= len(m.Replacement)
+		copy(dAtA[i:], m.Replacement)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Replacement)))
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x12
 	}
-	if m.Price != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.Price))
+	if len(m.Reason) > 0 {
+		i -= len(m.Reason)
+		copy(dAtA[i:], m.Reason)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Reason)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Readme) Marshal() (dAtA []byte, err error) {
+func (m *Release) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2282,12 +2920,12 @@ func (m *Readme) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Readme) MarshalTo(dAtA []byte) (int, error) {
+func (m *Release) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Readme) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Release) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2296,16 +2934,28 @@ func (m *Readme) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Text) > 0 {
-		i -= len(m.Text)
-		copy(dAtA[i:], m.Text)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
+	if m.Metadata != nil {
+		{
+			size, err := m.Metadata.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
 		i--
-		dAtA[i] = 0x1a
+		dAtA[i] = 0x52
 	}
-	if m.Plugin != nil {
+	if len(m.MinecraftVersion) > 0 {
+		i -= len(m.MinecraftVersion)
+		copy(dAtA[i:], m.MinecraftVersion)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.MinecraftVersion)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.Uploader != nil {
 		{
-			size, err := m.Plugin.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.Uploader.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -2313,6 +2963,44 @@ func (m *Readme) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintApi(dAtA, i, uint64(size))
 		}
 		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Channel) > 0 {
+		i -= len(m.Channel)
+		copy(dAtA[i:], m.Channel)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Channel)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.UploadedAt != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.UploadedAt))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Sha256) > 0 {
+		i -= len(m.Sha256)
+		copy(dAtA[i:], m.Sha256)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Sha256)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Size_ != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Size_))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.PluginId) > 0 {
+		i -= len(m.PluginId)
+		copy(dAtA[i:], m.PluginId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.PluginId)))
+		i--
 		dAtA[i] = 0x12
 	}
 	if len(m.Id) > 0 {
@@ -2325,7 +3013,7 @@ func (m *Readme) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *Session) Marshal() (dAtA []byte, err error) {
+func (m *Releases) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2335,12 +3023,12 @@ func (m *Session) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Session) MarshalTo(dAtA []byte) (int, error) {
+func (m *Releases) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Session) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Releases) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2349,29 +3037,24 @@ func (m *Session) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.LastRetrieved != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.LastRetrieved))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.UserId) > 0 {
-		i -= len(m.UserId)
-		copy(dAtA[i:], m.UserId)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.UserId)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Id) > 0 {
-		i -= len(m.Id)
-		copy(dAtA[i:], m.Id)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
-		i--
-		dAtA[i] = 0xa
+	if len(m.Releases) > 0 {
+		for iNdEx := len(m.Releases) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Releases[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SessionInsertResponse) Marshal() (dAtA []byte, err error) {
+func (m *Premium) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2381,12 +3064,12 @@ func (m *SessionInsertResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SessionInsertResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *Premium) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SessionInsertResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Premium) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2395,12 +3078,153 @@ func (m *SessionInsertResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Id) > 0 {
-		i -= len(m.Id)
-		copy(dAtA[i:], m.Id)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+	if m.Purchases != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Purchases))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x10
+	}
+	if m.Price != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Price))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Readme) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Readme) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Readme) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Text) > 0 {
+		i -= len(m.Text)
+		copy(dAtA[i:], m.Text)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Plugin != nil {
+		{
+			size, err := m.Plugin.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Session) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Session) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Session) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.ExpiresAt != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.ExpiresAt))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.LastRetrieved != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.LastRetrieved))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.UserId) > 0 {
+		i -= len(m.UserId)
+		copy(dAtA[i:], m.UserId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.UserId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SessionInsertResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SessionInsertResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SessionInsertResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
@@ -2429,6 +3253,13 @@ func (m *Changelog) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Channel) > 0 {
+		i -= len(m.Channel)
+		copy(dAtA[i:], m.Channel)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Channel)))
+		i--
+		dAtA[i] = 0x3a
+	}
 	if len(m.Updated) > 0 {
 		for iNdEx := len(m.Updated) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.Updated[iNdEx])
@@ -2621,10 +3452,6 @@ func (m *Purchase) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovApi(uint64(l))
 	}
-	l = len(m.Session)
-	if l > 0 {
-		n += 1 + l + sovApi(uint64(l))
-	}
 	if m.Complete {
 		n += 2
 	}
@@ -2724,6 +3551,33 @@ func (m *Plugin) Size() (n int) {
 	if m.LastUpdated != 0 {
 		n += 1 + sovApi(uint64(m.LastUpdated))
 	}
+	if m.Type != 0 {
+		n += 1 + sovApi(uint64(m.Type))
+	}
+	l = len(m.Channel)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if len(m.Channels) > 0 {
+		for k, v := range m.Channels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovApi(uint64(len(k))) + 1 + len(v) + sovApi(uint64(len(v)))
+			n += mapEntrySize + 1 + sovApi(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Yanked) > 0 {
+		for k, v := range m.Yanked {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovApi(uint64(len(k))) + 1 + len(v) + sovApi(uint64(len(v)))
+			n += mapEntrySize + 1 + sovApi(uint64(mapEntrySize))
+		}
+	}
+	if m.Deprecation != nil {
+		l = m.Deprecation.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2745,6 +3599,112 @@ func (m *PluginMetadata) Size() (n int) {
 			n += 1 + l + sovApi(uint64(l))
 		}
 	}
+	if len(m.Depend) > 0 {
+		for _, s := range m.Depend {
+			l = len(s)
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if len(m.Softdepend) > 0 {
+		for _, s := range m.Softdepend {
+			l = len(s)
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if len(m.Loadbefore) > 0 {
+		for _, s := range m.Loadbefore {
+			l = len(s)
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Deprecation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Replacement)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Release) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.PluginId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Size_ != 0 {
+		n += 1 + sovApi(uint64(m.Size_))
+	}
+	l = len(m.Sha256)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.UploadedAt != 0 {
+		n += 1 + sovApi(uint64(m.UploadedAt))
+	}
+	l = len(m.Channel)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Uploader != nil {
+		l = m.Uploader.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.MinecraftVersion)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Metadata != nil {
+		l = m.Metadata.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Releases) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Releases) > 0 {
+		for _, e := range m.Releases {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2810,6 +3770,9 @@ func (m *Session) Size() (n int) {
 	if m.LastRetrieved != 0 {
 		n += 1 + sovApi(uint64(m.LastRetrieved))
 	}
+	if m.ExpiresAt != 0 {
+		n += 1 + sovApi(uint64(m.ExpiresAt))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2868,6 +3831,10 @@ func (m *Changelog) Size() (n int) {
 			n += 1 + l + sovApi(uint64(l))
 		}
 	}
+	l = len(m.Channel)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -3312,11 +4279,11 @@ func (m *Purchase) Unmarshal(dAtA []byte) error {
 			}
 			m.ObjectId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Session", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Complete", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3326,12 +4293,652 @@ func (m *Purchase) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
+			m.Complete = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PaginatePluginsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PaginatePluginsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+			}
+			m.Page = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Page |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Search = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Category", wireType)
+			}
+			m.Category = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Category |= Category(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sort", wireType)
+			}
+			m.Sort = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sort |= Sort(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PaginatePluginsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PaginatePluginsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PaginatePluginsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Plugins", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Plugins = append(m.Plugins, &Plugin{})
+			if err := m.Plugins[len(m.Plugins)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Plugin) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Plugin: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Plugin: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Author == nil {
+				m.Author = &User{}
+			}
+			if err := m.Author.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Description", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Description = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Thumbnail", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Thumbnail = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Category", wireType)
+			}
+			m.Category = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Category |= Category(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = &PluginMetadata{}
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Premium", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Premium == nil {
+				m.Premium = &Premium{}
+			}
+			if err := m.Premium.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastUpdated", wireType)
+			}
+			m.LastUpdated = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastUpdated |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= ArtifactType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
 			if intStringLen < 0 {
 				return ErrInvalidLengthApi
 			}
@@ -3342,13 +4949,13 @@ func (m *Purchase) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Session = string(dAtA[iNdEx:postIndex])
+			m.Channel = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Complete", wireType)
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channels", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3358,12 +4965,282 @@ func (m *Purchase) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Complete = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Channels == nil {
+				m.Channels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthApi
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthApi
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthApi
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthApi
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipApi(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthApi
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Channels[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Yanked", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Yanked == nil {
+				m.Yanked = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthApi
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthApi
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthApi
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthApi
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipApi(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthApi
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Yanked[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deprecation", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Deprecation == nil {
+				m.Deprecation = &Deprecation{}
+			}
+			if err := m.Deprecation.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -3386,7 +5263,7 @@ func (m *Purchase) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
+func (m *PluginMetadata) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3409,17 +5286,17 @@ func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PaginatePluginsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: PluginMetadata: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PaginatePluginsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PluginMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Downloads", wireType)
 			}
-			m.Page = 0
+			m.Downloads = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3429,16 +5306,16 @@ func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Page |= int32(b&0x7F) << shift
+				m.Downloads |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Conflicts", wireType)
 			}
-			m.Count = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3448,14 +5325,27 @@ func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Count |= int32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Conflicts = append(m.Conflicts, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Search", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Depend", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3483,13 +5373,13 @@ func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Search = string(dAtA[iNdEx:postIndex])
+			m.Depend = append(m.Depend, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Category", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Softdepend", wireType)
 			}
-			m.Category = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3499,16 +5389,29 @@ func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Category |= Category(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Softdepend = append(m.Softdepend, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sort", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Loadbefore", wireType)
 			}
-			m.Sort = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3518,11 +5421,24 @@ func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Sort |= Sort(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Loadbefore = append(m.Loadbefore, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -3545,7 +5461,7 @@ func (m *PaginatePluginsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PaginatePluginsResponse) Unmarshal(dAtA []byte) error {
+func (m *Deprecation) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3568,17 +5484,17 @@ func (m *PaginatePluginsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PaginatePluginsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: Deprecation: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PaginatePluginsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Deprecation: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Plugins", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3588,25 +5504,55 @@ func (m *PaginatePluginsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthApi
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthApi
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Plugins = append(m.Plugins, &Plugin{})
-			if err := m.Plugins[len(m.Plugins)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Replacement", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.Replacement = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3630,7 +5576,7 @@ func (m *PaginatePluginsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Plugin) Unmarshal(dAtA []byte) error {
+func (m *Release) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3653,10 +5599,10 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Plugin: wiretype end group for non-group")
+			return fmt.Errorf("proto: Release: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Plugin: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Release: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3693,7 +5639,7 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PluginId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3721,13 +5667,13 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.PluginId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3737,33 +5683,29 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthApi
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthApi
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Author == nil {
-				m.Author = &User{}
-			}
-			if err := m.Author.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Version = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
 			}
-			var stringLen uint64
+			m.Size_ = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3773,27 +5715,14 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Size_ |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthApi
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthApi
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Version = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Description", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Sha256", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3821,13 +5750,13 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Description = string(dAtA[iNdEx:postIndex])
+			m.Sha256 = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Thumbnail", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UploadedAt", wireType)
 			}
-			var stringLen uint64
+			m.UploadedAt = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3837,29 +5766,16 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.UploadedAt |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthApi
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthApi
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Thumbnail = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Category", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channel", wireType)
 			}
-			m.Category = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3869,14 +5785,27 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Category |= Category(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Channel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Uploader", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3903,18 +5832,18 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Metadata == nil {
-				m.Metadata = &PluginMetadata{}
+			if m.Uploader == nil {
+				m.Uploader = &User{}
 			}
-			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Uploader.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Premium", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MinecraftVersion", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3924,33 +5853,29 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthApi
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthApi
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Premium == nil {
-				m.Premium = &Premium{}
-			}
-			if err := m.Premium.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.MinecraftVersion = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 10:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastUpdated", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
 			}
-			m.LastUpdated = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -3960,11 +5885,28 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastUpdated |= int64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = &PluginMetadata{}
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -3987,7 +5929,7 @@ func (m *Plugin) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PluginMetadata) Unmarshal(dAtA []byte) error {
+func (m *Releases) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4010,36 +5952,17 @@ func (m *PluginMetadata) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PluginMetadata: wiretype end group for non-group")
+			return fmt.Errorf("proto: Releases: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PluginMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Releases: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Downloads", wireType)
-			}
-			m.Downloads = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApi
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Downloads |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Conflicts", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Releases", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -4049,23 +5972,25 @@ func (m *PluginMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthApi
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthApi
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Conflicts = append(m.Conflicts, string(dAtA[iNdEx:postIndex]))
+			m.Releases = append(m.Releases, &Release{})
+			if err := m.Releases[len(m.Releases)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -4441,6 +6366,25 @@ func (m *Session) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiresAt", wireType)
+			}
+			m.ExpiresAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpiresAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -4767,6 +6711,38 @@ func (m *Changelog) Unmarshal(dAtA []byte) error {
 			}
 			m.Updated = append(m.Updated, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Channel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/spf13/cobra"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add plugins to your bundle.yml",
+	Long: `Add plugins to your bundle.yml without installing them. Use Plugin@version to specify a version
+	or version range, otherwise the latest version is used. With --env the plugins are added to that
+	environment`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please specify a plugin to add")
+		}
+
+		srv := file.NewLocalServer("")
+
+		bu, err := file.GetBundle(srv)
+		if err != nil {
+			return err
+		}
+		if err := addPlugins(bu, args); err != nil {
+			return err
+		}
+		if err := file.WriteBundle(srv, bu); err != nil {
+			return err
+		}
+		return printEnvPlugins(bu)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	addCmd.Flags().StringVarP(&environment, "env", "e", "", "environment of bundle.yml to add the plugins to, such as staging")
+}
+
+// addPlugins lists plugins given as Plugin@version, or just Plugin for the
+// latest version, in bundle.yml or in the environment when one is named. A
+// plugin that is already listed keeps the key it is listed under, whatever the
+// case it is given in
+func addPlugins(bu *file.BundleFile, args []string) error {
+	view, err := bu.ForEnv(environment)
+	if err != nil {
+		return err
+	}
+	for _, v := range args {
+		spl := strings.SplitN(v, "@", 2)
+		name, ver := spl[0], "latest"
+		if len(spl) > 1 {
+			ver = spl[1]
+		}
+		if key, ok := findPluginKey(view.Plugins, name); ok {
+			name = key
+		}
+		if err := bu.SetPlugin(environment, name, ver); err != nil {
+			return err
+		}
+		view.Plugins[name] = ver
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/c-bata/go-prompt"
+	. "github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+var bootstrapServer string
+
+var bootstrapMemory string
+
+var acceptEula bool
+
+type bootstrapResult struct {
+	Path   string             `json:"path"`
+	Server *file.LockedServer `json:"server"`
+	Eula   bool               `json:"eula"`
+}
+
+// bootstrapCmd represents the bootstrap command
+var bootstrapCmd = &cobra.Command{
+	Use:   "bootstrap [directory]",
+	Short: "Create a new server",
+	Long: `Create a server folder with a plugins folder, eula.txt, server.properties, start scripts and a
+	bundle.yml, then download the server software into it. The server software is pinned in the
+	Server section of bundle.yml so that "bundle install" keeps it up to date like your plugins.
+	The folder is called server unless another one is specified`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "server"
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+			return fmt.Errorf("%s already exists and is not empty", dir)
+		}
+
+		software := bootstrapServer
+		if software == "" && isInteractive() {
+			term.Println("Which server software would you like to run? (name@version, the version is optional)")
+			software = prompt.Input(">> ", nilCompleter)
+		}
+		software = strings.TrimSpace(software)
+		if software == "" {
+			return errors.New("specify the server software with --server, for example --server Paper@1.17.1")
+		}
+
+		sw := &file.ServerSoftware{Name: software, Version: "latest"}
+		if spl := strings.SplitN(software, "@", 2); len(spl) == 2 {
+			sw.Name, sw.Version = spl[0], spl[1]
+		}
+
+		resolved, err := resolveServer(sw)
+		if err != nil {
+			return err
+		}
+		sw.Name = resolved.Name
+		sw.Version = resolved.Version
+
+		if err := os.MkdirAll(filepath.Join(dir, "plugins"), os.ModePerm); err != nil {
+			return err
+		}
+		srv := file.NewLocalServer(dir)
+
+		accepted := acceptEula || confirm("Do you accept the Minecraft EULA (https://account.mojang.com/documents/minecraft_eula)?", false)
+		if err := file.WriteEula(srv, accepted); err != nil {
+			return err
+		}
+		if err := file.WriteServerProperties(srv); err != nil {
+			return err
+		}
+		if err := file.WriteStartScripts(srv, sw.JarName(), bootstrapMemory); err != nil {
+			return err
+		}
+		if err := os.Chmod(filepath.Join(dir, file.StartScriptName), 0755); err != nil {
+			return err
+		}
+		if err := file.WriteBundle(srv, &file.BundleFile{Server: sw}); err != nil {
+			return err
+		}
+
+		report, locked := installServer(cmd.Context(), srv, sw, currentToken(), nil, false)
+		if report.Outcome == outcomeFailed {
+			return fmt.Errorf("could not install %s %s: %s", report.Plugin, report.Version, report.Reason)
+		}
+		if err := file.WriteLock(srv, &file.BundleLock{Server: locked}); err != nil {
+			return err
+		}
+
+		if isJSONOutput() {
+			return printJSON(bootstrapResult{Path: dir, Server: locked, Eula: accepted})
+		}
+		term.Println(Green(fmt.Sprintf("Created a %s %s server in %s", sw.Name, sw.Version, dir)).Bold())
+		if !accepted {
+			term.Println(Yellow("The server will not start until you accept the EULA in eula.txt"))
+		}
+		term.Println(fmt.Sprintf("Add plugins with \"bundle add\" inside %s and start the server with %s", dir, file.StartScriptName))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bootstrapCmd)
+	bootstrapCmd.Flags().StringVarP(&bootstrapServer, "server", "s", "", "server software to run as name@version, such as Paper@1.17.1")
+	bootstrapCmd.Flags().StringVarP(&bootstrapMemory, "memory", "m", "2G", "memory that the start scripts give the server")
+	bootstrapCmd.Flags().BoolVar(&acceptEula, "accept-eula", false, "accept the Minecraft EULA without asking")
+}
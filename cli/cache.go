@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/bennycio/bundle/cli/cache"
+	"github.com/bennycio/bundle/cli/term"
+	. "github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+var pruneOlderThan time.Duration
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of downloaded plugins",
+	Long: `Every jar that is installed is kept in ~/.bundle/cache so that installing the same version
+	again, on this or any other server of this machine, does not download it again. Use
+	"bundle install --offline" to install only from the cache`,
+}
+
+// cacheListCmd represents the cache list command
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the cached plugin versions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := cache.List()
+		if err != nil {
+			return err
+		}
+		if isJSONOutput() {
+			return printJSON(entries)
+		}
+
+		table := simpletable.New()
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Text: "Plugin"},
+				{Text: "Version"},
+				{Text: "Size"},
+				{Text: "Last Used"},
+			},
+		}
+		var total int64
+		for _, v := range entries {
+			table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+				{Text: v.Plugin},
+				{Text: v.Version},
+				{Text: byteSize(v.Size)},
+				{Text: v.LastUsed.Format("2006-01-02")},
+			})
+			if v.Size > 0 {
+				total += v.Size
+			}
+		}
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println(table.String())
+		term.Println(fmt.Sprintf("%d cached versions, %s", len(entries), byteSize(total)))
+		return nil
+	},
+}
+
+// cachePruneCmd represents the cache prune command
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached plugins that have not been used for a while",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := cache.Prune(time.Now().Add(-pruneOlderThan))
+		if err != nil {
+			return err
+		}
+		if isJSONOutput() {
+			return printJSON(removed)
+		}
+		for _, v := range removed {
+			term.Println(fmt.Sprintf("Removed %s %s", v.Plugin, v.Version))
+		}
+		term.Println(Green(fmt.Sprintf("Pruned %d cached versions", len(removed))).Bold())
+		return nil
+	},
+}
+
+// cacheCleanCmd represents the cache clean command
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove every cached plugin",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !confirm("Remove every cached plugin?", true) {
+			return nil
+		}
+		if err := cache.Clean(); err != nil {
+			return err
+		}
+		if isJSONOutput() {
+			return printJSON(map[string]bool{"cleaned": true})
+		}
+		term.Println(Green("Cleaned the cache").Bold())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+	cachePruneCmd.Flags().DurationVar(&pruneOlderThan, "older-than", 30*24*time.Hour, "remove versions that have not been installed for this long")
+}
+
+// byteSize formats a number of bytes for people
+func byteSize(n int64) string {
+	if n < 0 {
+		return "missing"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,351 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bennycio/bundle/internal/version"
+)
+
+// The cache keeps every downloaded jar once, named after the SHA-256 digest of
+// its contents, in the blobs folder. The refs folder maps a plugin and version
+// to a digest with one small file per version, refs/<plugin>/<version>, so
+// that several installs can add to the cache at the same time
+const (
+	blobsDir = "blobs"
+	refsDir  = "refs"
+)
+
+// ErrNotCached is returned when a plugin version is not in the cache
+var ErrNotCached = errors.New("not in the cache")
+
+// Entry is a cached version of a plugin
+type Entry struct {
+	Plugin   string    `json:"plugin"`
+	Version  string    `json:"version"`
+	Sha256   string    `json:"sha256"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// Dir returns the folder of the cache, ~/.bundle/cache
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".bundle", "cache"), nil
+}
+
+func blobPath(dir, sum string) string {
+	return filepath.Join(dir, blobsDir, sum+".jar")
+}
+
+// Lookup returns the path of a cached jar and its digest. The jar is hashed
+// again so that a damaged cache entry is never installed, damaged entries are
+// removed
+func Lookup(pluginName, pluginVersion string) (string, string, error) {
+	if err := CheckRef(pluginName, pluginVersion); err != nil {
+		return "", "", err
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", "", err
+	}
+
+	name, ok := findPlugin(dir, pluginName)
+	if !ok {
+		return "", "", ErrNotCached
+	}
+	ref := filepath.Join(dir, refsDir, name, pluginVersion)
+	bs, err := os.ReadFile(ref)
+	if err != nil {
+		return "", "", ErrNotCached
+	}
+	sum := strings.TrimSpace(string(bs))
+	if !isDigest(sum) {
+		os.Remove(ref)
+		return "", "", ErrNotCached
+	}
+
+	fp := blobPath(dir, sum)
+	actual, err := hashFile(fp)
+	if err != nil {
+		os.Remove(ref)
+		return "", "", ErrNotCached
+	}
+	if actual != sum {
+		os.Remove(fp)
+		os.Remove(ref)
+		return "", "", ErrNotCached
+	}
+
+	now := time.Now()
+	os.Chtimes(fp, now, now)
+	return fp, sum, nil
+}
+
+// Store moves a downloaded jar into the cache and returns its new path
+func Store(pluginName, pluginVersion, src, sum string) (string, error) {
+	if err := CheckRef(pluginName, pluginVersion); err != nil {
+		return "", err
+	}
+	if !isDigest(sum) {
+		return "", fmt.Errorf("%q is not a SHA-256 digest", sum)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, blobsDir), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	name, ok := findPlugin(dir, pluginName)
+	if !ok {
+		name = pluginName
+	}
+	if err := os.MkdirAll(filepath.Join(dir, refsDir, name), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	fp := blobPath(dir, sum)
+	if _, err := os.Stat(fp); err == nil {
+		os.Remove(src)
+	} else if err := moveFile(src, fp); err != nil {
+		return "", err
+	}
+
+	ref := filepath.Join(dir, refsDir, name, pluginVersion)
+	if err := os.WriteFile(ref, []byte(sum+"\n"), 0644); err != nil {
+		return "", err
+	}
+	return fp, nil
+}
+
+// Versions lists the cached versions of a plugin, newest first, along with the
+// name the plugin is cached under
+func Versions(pluginName string) (string, []string, error) {
+	if err := checkName("plugin", pluginName); err != nil {
+		return "", nil, err
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", nil, err
+	}
+	name, ok := findPlugin(dir, pluginName)
+	if !ok {
+		return pluginName, []string{}, nil
+	}
+	infos, err := os.ReadDir(filepath.Join(dir, refsDir, name))
+	if err != nil {
+		return "", nil, err
+	}
+	result := []string{}
+	for _, v := range infos {
+		if !v.IsDir() {
+			result = append(result, v.Name())
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return version.Compare(result[i], result[j]) > 0
+	})
+	return name, result, nil
+}
+
+// List returns every cached plugin version sorted by plugin and version
+func List() ([]Entry, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Entry{}
+	plugins, err := os.ReadDir(filepath.Join(dir, refsDir))
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range plugins {
+		if !p.IsDir() {
+			continue
+		}
+		_, versions, err := Versions(p.Name())
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			bs, err := os.ReadFile(filepath.Join(dir, refsDir, p.Name(), v))
+			if err != nil {
+				continue
+			}
+			e := Entry{Plugin: p.Name(), Version: v, Sha256: strings.TrimSpace(string(bs)), Size: -1}
+			if fi, err := os.Stat(blobPath(dir, e.Sha256)); err == nil {
+				e.Size = fi.Size()
+				e.LastUsed = fi.ModTime()
+			}
+			result = append(result, e)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Plugin) < strings.ToLower(result[j].Plugin)
+	})
+	return result, nil
+}
+
+// Prune removes every cached jar that has not been used since the given time
+// along with references to jars that are missing and jars that nothing refers
+// to. It returns the entries that were removed
+func Prune(before time.Time) ([]Entry, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []Entry{}
+	used := map[string]bool{}
+	for _, v := range entries {
+		if v.Size < 0 || v.LastUsed.Before(before) {
+			if err := os.Remove(filepath.Join(dir, refsDir, v.Plugin, v.Version)); err != nil {
+				return removed, err
+			}
+			removed = append(removed, v)
+			continue
+		}
+		used[v.Sha256] = true
+	}
+
+	blobs, err := os.ReadDir(filepath.Join(dir, blobsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return removed, err
+	}
+	for _, v := range blobs {
+		if !used[strings.TrimSuffix(v.Name(), ".jar")] {
+			if err := os.Remove(filepath.Join(dir, blobsDir, v.Name())); err != nil {
+				return removed, err
+			}
+		}
+	}
+
+	plugins, err := os.ReadDir(filepath.Join(dir, refsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return removed, err
+	}
+	for _, v := range plugins {
+		// only succeeds for folders that are empty now
+		os.Remove(filepath.Join(dir, refsDir, v.Name()))
+	}
+	return removed, nil
+}
+
+// Clean removes the whole cache
+func Clean() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// checkRef makes sure that a plugin and version can name their file in the
+// refs, refs/<plugin>/<version>, without leaving the refs folder
+func CheckRef(pluginName, pluginVersion string) error {
+	if err := checkName("plugin", pluginName); err != nil {
+		return err
+	}
+	return checkName("version", pluginVersion)
+}
+
+func checkName(kind, name string) error {
+	if name == "" || name == "." || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") || filepath.Base(name) != name {
+		return fmt.Errorf("%s %q cannot be cached, it is not a valid file name", kind, name)
+	}
+	return nil
+}
+
+// isDigest reports whether sum is a hex encoded SHA-256 digest, the name of a
+// blob
+func isDigest(sum string) bool {
+	bs, err := hex.DecodeString(sum)
+	return err == nil && len(bs) == sha256.Size
+}
+
+// findPlugin finds the folder of a plugin in the refs, ignoring case
+func findPlugin(dir, pluginName string) (string, bool) {
+	plugins, err := os.ReadDir(filepath.Join(dir, refsDir))
+	if err != nil {
+		return "", false
+	}
+	for _, v := range plugins {
+		if v.IsDir() && strings.EqualFold(v.Name(), pluginName) {
+			return v.Name(), true
+		}
+	}
+	return "", false
+}
+
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	// the download folder may be on another device, copy through a temporary
+	// file so that a partial copy is never seen as a cached jar
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return fmt.Errorf("could not add %s to the cache: %v", filepath.Base(dst), err)
+	}
+	in.Close()
+	os.Remove(src)
+	return nil
+}
+
+func hashFile(fp string) (string, error) {
+	fi, err := os.Open(fp)
+	if err != nil {
+		return "", err
+	}
+	defer fi.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, fi); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
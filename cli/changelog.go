@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/c-bata/go-prompt"
+	. "github.com/logrusorgru/aurora"
+	"gopkg.in/yaml.v2"
+)
+
+// makeChangelog builds the changelog of a new version. It is read from the
+// --changelog file, from git with --changelog-from-git, from a CHANGELOG.md in
+// the working directory that has a section for the version or else typed at a
+// prompt, and then previewed so that it can be edited before it is uploaded
+func makeChangelog(pluginId string, previous string, version string) (*api.Changelog, error) {
+	var entry *file.ChangelogEntry
+	var err error
+
+	switch {
+	case changelogFromGit:
+		entry, err = gitChangelog(previous, version)
+	case changelogPath != "":
+		entry, err = file.ReadChangelog(changelogPath, version)
+	default:
+		// without a CHANGELOG.md, or without the version in it, the changes
+		// are typed instead, but a CHANGELOG.md that cannot be read is an error
+		entry, err = file.ReadChangelog("CHANGELOG.md", version)
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, file.ErrNoChanges) {
+			entry, err = promptChangelog(), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err = reviewChangelog(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Changelog{
+		PluginId: pluginId,
+		Version:  version,
+		Added:    entry.Added,
+		Removed:  entry.Removed,
+		Updated:  entry.Updated,
+	}, nil
+}
+
+// gitChangelog reads the conventional commits since the tag of the previous
+// version, either 1.2.0 or v1.2.0. Without such a tag the newest tag other
+// than the one of the new version is used
+func gitChangelog(previous string, version string) (*file.ChangelogEntry, error) {
+	since := ""
+	for _, v := range []string{previous, "v" + previous} {
+		if previous == "" {
+			break
+		}
+		if _, err := git("rev-parse", "-q", "--verify", "refs/tags/"+v); err == nil {
+			since = v
+			break
+		}
+	}
+	if since == "" {
+		tag, err := git("describe", "--tags", "--abbrev=0", "--exclude", version, "--exclude", "v"+version)
+		if err != nil {
+			return nil, errors.New("no git tag for the previous version " + previous)
+		}
+		since = tag
+	}
+
+	out, err := git("log", "--no-merges", "--format=%s", since+"..HEAD")
+	if err != nil {
+		return nil, err
+	}
+	subjects := []string{}
+	for _, v := range strings.Split(out, "\n") {
+		if v != "" {
+			subjects = append(subjects, v)
+		}
+	}
+	return file.ParseConventionalCommits(subjects), nil
+}
+
+func git(args ...string) (string, error) {
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if stderr.Len() > 0 {
+			return "", errors.New(strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func promptChangelog() *file.ChangelogEntry {
+	entry := &file.ChangelogEntry{}
+	if !isInteractive() {
+		return entry
+	}
+
+	entry.Added = promptList("What did you add in this version?")
+	entry.Removed = promptList("What did you remove in this version?")
+	entry.Updated = promptList("What did you update in this version?")
+	return entry
+}
+
+func promptList(question string) []string {
+	result := []string{}
+	term.Println(question)
+	term.Println(Gray(12, "Press enter on an empty line to continue"))
+	for {
+		line := prompt.Input(">> ", nilCompleter)
+		if strings.Trim(strings.TrimSpace(line), "\n") == "" {
+			break
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// reviewChangelog shows a changelog and lets it be accepted, edited in an
+// editor or typed again
+func reviewChangelog(entry *file.ChangelogEntry) (*file.ChangelogEntry, error) {
+	for {
+		if !isJSONOutput() {
+			printChangelog(entry)
+		}
+		if !isInteractive() {
+			return entry, nil
+		}
+
+		term.Println("Is this correct? [Y/n/e]")
+		term.Println(Gray(12, "Answer n to type the changelog again or e to edit it"))
+		answer := prompt.Input(">> ", editCompleter)
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "", "y", "yes":
+			return entry, nil
+		case "e", "edit":
+			edited, err := editChangelog(entry)
+			if err != nil {
+				term.Println(Red(err.Error()))
+				continue
+			}
+			entry = edited
+		default:
+			entry = promptChangelog()
+		}
+	}
+}
+
+func printChangelog(entry *file.ChangelogEntry) {
+	fmt.Fprintln(term.Output, Green("Added: ").Bold())
+	for _, v := range entry.Added {
+		fmt.Fprintf(term.Output, "  - %s\n", Green(v))
+	}
+	fmt.Fprintln(term.Output, Red("Removed: ").Bold())
+	for _, v := range entry.Removed {
+		fmt.Fprintf(term.Output, "  - %s\n", Red(v))
+	}
+	fmt.Fprintln(term.Output, Blue("Updated: ").Bold())
+	for _, v := range entry.Updated {
+		fmt.Fprintf(term.Output, "  - %s\n", Blue(v))
+	}
+}
+
+// editChangelog opens a changelog as YAML in $VISUAL or $EDITOR
+func editChangelog(entry *file.ChangelogEntry) (*file.ChangelogEntry, error) {
+	bs, err := yaml.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "bundle-changelog-*.yml")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(bs); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	// editors such as code -w come with arguments
+	args := strings.Fields(editor())
+	cmd := exec.Command(args[0], append(args[1:], tmp.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	bs, err = os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	edited := &file.ChangelogEntry{}
+	if err := yaml.UnmarshalStrict(bs, edited); err != nil {
+		return nil, err
+	}
+	return edited, nil
+}
+
+func editor() string {
+	for _, v := range []string{"VISUAL", "EDITOR"} {
+		if e := os.Getenv(v); e != "" {
+			return e
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+func editCompleter(d prompt.Document) []prompt.Suggest {
+	s := []prompt.Suggest{
+		{Text: "y"},
+		{Text: "n"},
+		{Text: "e"},
+	}
+	return prompt.FilterHasPrefix(s, d.GetWordBeforeCursor(), true)
+}
@@ -11,6 +11,9 @@ var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Print path to config file",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if isJSONOutput() {
+			return printJSON(map[string]string{"path": configPath})
+		}
 		fmt.Println(configPath)
 		return nil
 	},
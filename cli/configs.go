@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	. "github.com/logrusorgru/aurora"
+)
+
+const (
+	outcomeDeployed = "deployed"
+)
+
+// configReport is what happened to a single config file during an install
+type configReport struct {
+	Path     string `json:"path"`
+	Template string `json:"template"`
+	Outcome  string `json:"outcome"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// deployConfigs renders the config templates of a bundle and writes them to a
+// server. Templates are read from the working directory, which is where the
+// repository that holds bundle.yml is checked out. A config file that was
+// edited on the server since it was last deployed is only replaced after its
+// diff is shown and the replacement is confirmed, or when overwrite is set
+func deployConfigs(srv file.Server, bundle *file.BundleFile, overwrite bool) []configReport {
+	result := []configReport{}
+	if len(bundle.Configs) == 0 {
+		return result
+	}
+
+	deployed, err := file.GetDeployedConfigs(srv)
+	if err != nil {
+		return append(result, configReport{Path: file.DeployedConfigsFile, Outcome: outcomeFailed, Reason: err.Error()})
+	}
+
+	paths := []string{}
+	for k := range bundle.Configs {
+		paths = append(paths, k)
+	}
+	sort.Strings(paths)
+
+	templates := file.NewLocalServer("")
+	for _, v := range paths {
+		report := configReport{Path: v, Template: bundle.Configs[v]}
+		report.Outcome, report.Reason = deployConfig(srv, templates, v, bundle.Configs[v], bundle.Variables, deployed, overwrite)
+		result = append(result, report)
+	}
+
+	if err := file.WriteDeployedConfigs(srv, deployed); err != nil {
+		result = append(result, configReport{Path: file.DeployedConfigsFile, Outcome: outcomeFailed, Reason: err.Error()})
+	}
+	return result
+}
+
+// deployConfig renders a single template to a path on the server and records
+// what was deployed
+func deployConfig(srv, templates file.Server, name, template string, vars map[string]string, deployed map[string]string, overwrite bool) (string, string) {
+	p, err := file.CleanConfigPath(name)
+	if err != nil {
+		return outcomeFailed, err.Error()
+	}
+	bs, err := file.ReadFile(templates, template)
+	if err != nil {
+		return outcomeFailed, err.Error()
+	}
+	rendered, err := file.RenderTemplate(string(bs), vars)
+	if err != nil {
+		return outcomeFailed, fmt.Sprintf("cannot render %s: %s", template, err.Error())
+	}
+	sum := file.ContentHash([]byte(rendered))
+
+	if srv.Exists(p) {
+		current, err := file.ReadFile(srv, p)
+		if err != nil {
+			return outcomeFailed, err.Error()
+		}
+		currentSum := file.ContentHash(current)
+		if currentSum == sum {
+			deployed[p] = sum
+			return outcomeSkipped, "up to date"
+		}
+		if currentSum != deployed[p] && !overwrite {
+			if !isJSONOutput() {
+				term.Println(Yellow(fmt.Sprintf("%s was edited on the server since it was last deployed", p)).Bold())
+				printDiff(p, template, string(current), rendered)
+			}
+			if !confirm(fmt.Sprintf("Overwrite %s?", p), false) {
+				return outcomeSkipped, "edited on the server"
+			}
+		}
+	}
+
+	if dir := path.Dir(p); dir != "." {
+		if err := srv.MkdirAll(dir); err != nil {
+			return outcomeFailed, err.Error()
+		}
+	}
+	if err := srv.WriteFile(p, strings.NewReader(rendered)); err != nil {
+		return outcomeFailed, err.Error()
+	}
+	deployed[p] = sum
+	return outcomeDeployed, ""
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal/gate"
+	. "github.com/logrusorgru/aurora"
+)
+
+// checkConflicts makes sure that none of the plugins that will be present once
+// the given plugins are installed declare a conflict with each other. Conflicts
+// are only installed when the user confirms them or the install is forced
+func checkConflicts(srv file.Server, plugins map[string]string) error {
+	conflicts := installedConflicts(srv)
+
+	gs := gate.NewGateService("localhost", "8020")
+	for k := range plugins {
+		dbpl, err := gs.GetPlugin(&api.Plugin{Name: k})
+		if err != nil {
+			continue
+		}
+		for name := range conflicts {
+			if strings.EqualFold(name, dbpl.Name) {
+				delete(conflicts, name)
+			}
+		}
+		conflicts[dbpl.Name] = dbpl.Metadata.GetConflicts()
+	}
+
+	pairs := conflictingPairs(conflicts)
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	term.Println(Red("The following plugins conflict with each other:").Bold())
+	for _, v := range pairs {
+		term.Println(Red(fmt.Sprintf("  - %s", v)))
+	}
+
+	if force || confirm("Would you like to install them anyway?", false) {
+		return nil
+	}
+	return errors.New("installation cancelled because of conflicting plugins")
+}
+
+// installedConflicts reads the conflicts of every jar in the plugins folder,
+// keyed by the name in its plugin.yml
+func installedConflicts(srv file.Server) map[string][]string {
+	result := map[string][]string{}
+
+	names, err := listPluginJars(srv)
+	if err != nil {
+		return result
+	}
+
+	for _, v := range names {
+		plyml, err := file.GetPluginYml(srv, v)
+		if err != nil || plyml.Name == "" {
+			continue
+		}
+		result[plyml.Name] = plyml.Conflicts
+	}
+	return result
+}
+
+// listPluginJars returns the names of the jars in the plugins folder without
+// their extension
+func listPluginJars(srv file.Server) ([]string, error) {
+	names, err := srv.ReadDir("plugins")
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, v := range names {
+		if strings.HasSuffix(v, ".jar") {
+			result = append(result, strings.TrimSuffix(v, ".jar"))
+		}
+	}
+	return result, nil
+}
+
+// conflictingPairs describes every pair of plugins where either plugin lists the
+// other as a conflict
+func conflictingPairs(conflicts map[string][]string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+
+	for name, list := range conflicts {
+		for _, c := range list {
+			for other := range conflicts {
+				if !strings.EqualFold(other, c) || strings.EqualFold(other, name) {
+					continue
+				}
+				a, b := name, other
+				if strings.ToLower(a) > strings.ToLower(b) {
+					a, b = b, a
+				}
+				key := strings.ToLower(a + "/" + b)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				result = append(result, fmt.Sprintf("%s conflicts with %s", a, b))
+			}
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,201 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The API token of the CLI is kept in the keyring of the operating system.
+// Machines without one, such as most servers, keep it in a file next to the
+// config file instead that only its owner can read. The file is encrypted with
+// a key derived from the passphrase in BUNDLE_TOKEN_PASSPHRASE when it is set,
+// otherwise the token is kept in plain text
+const (
+	keyringService = "bundle"
+	keyringUser    = "api-token"
+	tokenFileName  = "token"
+
+	// EnvToken is the environment variable that overrides the saved token,
+	// which is handy in CI
+	EnvToken = "BUNDLE_TOKEN"
+	// EnvPassphrase is the environment variable that holds the passphrase
+	// that the token file is encrypted with
+	EnvPassphrase = "BUNDLE_TOKEN_PASSPHRASE"
+
+	// encryptedPrefix marks a token file that is encrypted
+	encryptedPrefix = "scrypt:"
+	saltSize        = 16
+)
+
+const (
+	StoreKeyring       = "keyring"
+	StoreFile          = "plaintext file"
+	StoreEncryptedFile = "encrypted file"
+	StoreEnv           = "environment"
+)
+
+// ErrNotLoggedIn is returned when there is no saved token
+var ErrNotLoggedIn = errors.New("not logged in, run bundle login")
+
+// Save keeps a token in the keyring or, when there is none, in the token file,
+// encrypted when a passphrase is set. It reports where the token was kept
+func Save(token string) (string, error) {
+	if err := keyring.Set(keyringService, keyringUser, token); err == nil {
+		// a token saved to the file before the keyring was available is stale
+		if fp, err := TokenFile(); err == nil {
+			os.Remove(fp)
+		}
+		return StoreKeyring, nil
+	}
+
+	fp, err := TokenFile()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(fp), os.ModePerm); err != nil {
+		return "", err
+	}
+	// WriteFile keeps the mode of a file that exists, so a token file that
+	// others could read is replaced rather than written to
+	if err := os.Remove(fp); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	contents, store := token, StoreFile
+	if passphrase := os.Getenv(EnvPassphrase); passphrase != "" {
+		sealed, err := seal([]byte(token), passphrase)
+		if err != nil {
+			return "", err
+		}
+		contents, store = encryptedPrefix+hex.EncodeToString(sealed), StoreEncryptedFile
+	}
+	if err := os.WriteFile(fp, []byte(contents), 0600); err != nil {
+		return "", err
+	}
+	return store, nil
+}
+
+// Load returns the saved token and where it was found
+func Load() (string, string, error) {
+	if v := strings.TrimSpace(os.Getenv(EnvToken)); v != "" {
+		return v, StoreEnv, nil
+	}
+	if v, err := keyring.Get(keyringService, keyringUser); err == nil && v != "" {
+		return v, StoreKeyring, nil
+	}
+
+	fp, err := TokenFile()
+	if err != nil {
+		return "", "", err
+	}
+	bs, err := os.ReadFile(fp)
+	if os.IsNotExist(err) {
+		return "", "", ErrNotLoggedIn
+	}
+	if err != nil {
+		return "", "", err
+	}
+	token := strings.TrimSpace(string(bs))
+	if token == "" {
+		return "", "", ErrNotLoggedIn
+	}
+	if !strings.HasPrefix(token, encryptedPrefix) {
+		return token, StoreFile, nil
+	}
+
+	passphrase := os.Getenv(EnvPassphrase)
+	if passphrase == "" {
+		return "", "", errors.New("the saved token is encrypted, set " + EnvPassphrase + " to read it")
+	}
+	sealed, err := hex.DecodeString(strings.TrimPrefix(token, encryptedPrefix))
+	if err != nil {
+		return "", "", err
+	}
+	plain, err := open(sealed, passphrase)
+	if err != nil {
+		return "", "", errors.New("the saved token cannot be decrypted, check " + EnvPassphrase + " or run bundle login again")
+	}
+	return string(plain), StoreEncryptedFile, nil
+}
+
+// Delete forgets the saved token wherever it is kept
+func Delete() error {
+	if err := keyring.Delete(keyringService, keyringUser); err != nil && err != keyring.ErrNotFound {
+		// without a keyring there is nothing to delete from it
+		if _, gerr := keyring.Get(keyringService, keyringUser); gerr == nil {
+			return err
+		}
+	}
+	fp, err := TokenFile()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fp); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// TokenFile is where the token is kept when there is no keyring
+func TokenFile() (string, error) {
+	confDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(confDir, ".bundle", tokenFileName), nil
+}
+
+// newGCM derives a key from the passphrase with scrypt, salted so that the same
+// passphrase never gives the same key twice
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(c)
+}
+
+// seal encrypts plaintext into its salt, nonce and ciphertext
+func seal(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(append(salt, nonce...), nonce, plaintext, nil), nil
+}
+
+func open(sealed []byte, passphrase string) ([]byte, error) {
+	if len(sealed) < saltSize {
+		return nil, errors.New("token file is too short")
+	}
+	gcm, err := newGCM(passphrase, sealed[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	sealed = sealed[saltSize:]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("token file is too short")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	. "github.com/logrusorgru/aurora"
+)
+
+var mergeDefaults bool
+
+// configRename is a setting of the default config that moved to another key
+type configRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// configChanges are the differences between the default config.yml of the
+// jar that an update installs and the live config.yml of the plugin
+type configChanges struct {
+	Path    string         `json:"path"`
+	Added   []string       `json:"added,omitempty"`
+	Removed []string       `json:"removed,omitempty"`
+	Renamed []configRename `json:"renamed,omitempty"`
+	Merged  bool           `json:"merged"`
+	// defaults are the settings of the new default config and replaced is the
+	// version of the jar that the update replaces
+	defaults map[string]interface{}
+	replaced string
+}
+
+// compareDefaultConfigs compares the default config.yml of the installed jar
+// of a plugin and of the jar that replaces it with the live config.yml. The
+// live config is missing the settings that the new default config added, has
+// the ones that it removed, and has the ones it renamed under their old key.
+// It returns nil when nothing has to change or any of them is missing
+func compareDefaultConfigs(srv file.Server, job *installJob) *configChanges {
+	oldJar, err := file.ReadFile(srv, fmt.Sprintf("plugins/%s.jar", job.Plugin.Name))
+	if err != nil {
+		return nil
+	}
+	oldYml, err := file.ParsePluginYml(bytes.NewReader(oldJar), int64(len(oldJar)))
+	if err != nil {
+		return nil
+	}
+	newJar, err := os.ReadFile(job.Path)
+	if err != nil {
+		return nil
+	}
+	plyml, err := file.ParsePluginYml(bytes.NewReader(newJar), int64(len(newJar)))
+	if err != nil || plyml.Name == "" {
+		return nil
+	}
+	livePath := file.LiveConfigPath(plyml.Name)
+	if !srv.Exists(livePath) {
+		return nil
+	}
+
+	oldDefaults, err := file.ReadDefaultConfig(bytes.NewReader(oldJar), int64(len(oldJar)))
+	if err != nil {
+		return nil
+	}
+	newDefaults, err := file.ReadDefaultConfig(bytes.NewReader(newJar), int64(len(newJar)))
+	if err != nil || newDefaults == nil {
+		return nil
+	}
+	live, err := file.ReadFile(srv, livePath)
+	if err != nil {
+		return nil
+	}
+
+	oldKeys, err := file.FlattenYml(oldDefaults)
+	if err != nil {
+		return nil
+	}
+	newKeys, err := file.FlattenYml(newDefaults)
+	if err != nil {
+		return nil
+	}
+	liveKeys, err := file.FlattenYml(live)
+	if err != nil {
+		return nil
+	}
+
+	changes := diffDefaults(oldKeys, newKeys, liveKeys)
+	if len(changes.Added) == 0 && len(changes.Removed) == 0 && len(changes.Renamed) == 0 {
+		return nil
+	}
+	changes.Path = livePath
+	changes.replaced = oldYml.Version
+	return changes
+}
+
+// diffDefaults works out what changed between two default configs from the
+// point of view of the live config
+func diffDefaults(oldKeys, newKeys, liveKeys []file.ConfigKey) *configChanges {
+	oldSet := keySet(oldKeys)
+	newSet := keySet(newKeys)
+	liveSet := keySet(liveKeys)
+
+	gone := []file.ConfigKey{}
+	for _, v := range oldKeys {
+		if _, ok := newSet[v.Path]; !ok {
+			gone = append(gone, v)
+		}
+	}
+	fresh := []file.ConfigKey{}
+	for _, v := range newKeys {
+		if _, ok := oldSet[v.Path]; !ok {
+			fresh = append(fresh, v)
+		}
+	}
+
+	changes := &configChanges{defaults: newSet}
+	renamedFrom := map[string]bool{}
+	renamedTo := map[string]bool{}
+	match := func(same func(a, b file.ConfigKey) bool) {
+		for _, g := range gone {
+			if renamedFrom[g.Path] {
+				continue
+			}
+			for _, f := range fresh {
+				if renamedTo[f.Path] || !same(g, f) {
+					continue
+				}
+				renamedFrom[g.Path], renamedTo[f.Path] = true, true
+				_, hasOld := liveSet[g.Path]
+				_, hasNew := liveSet[f.Path]
+				if hasOld && !hasNew {
+					changes.Renamed = append(changes.Renamed, configRename{From: g.Path, To: f.Path})
+				}
+				break
+			}
+		}
+	}
+	match(func(a, b file.ConfigKey) bool {
+		return lastKey(a.Path) == lastKey(b.Path)
+	})
+	match(func(a, b file.ConfigKey) bool {
+		av := fmt.Sprint(a.Value)
+		return av != "" && av != "<nil>" && av == fmt.Sprint(b.Value)
+	})
+
+	for _, v := range newKeys {
+		if _, ok := liveSet[v.Path]; !ok && !renamedTo[v.Path] {
+			changes.Added = append(changes.Added, v.Path)
+		}
+	}
+	for _, v := range gone {
+		if _, ok := liveSet[v.Path]; ok && !renamedFrom[v.Path] {
+			changes.Removed = append(changes.Removed, v.Path)
+		}
+	}
+	return changes
+}
+
+// mergeNewDefaults adds the settings that the new default config added to the
+// live config and moves renamed settings to their new key, keeping a copy of
+// the live config in the history store first. Removed settings are left alone
+func mergeNewDefaults(srv file.Server, pluginName string, changes *configChanges) error {
+	live, err := file.ReadFile(srv, changes.Path)
+	if err != nil {
+		return err
+	}
+	liveSet := map[string]interface{}{}
+	if keys, err := file.FlattenYml(live); err == nil {
+		liveSet = keySet(keys)
+	}
+
+	set := []file.ConfigKey{}
+	for _, v := range changes.Added {
+		set = append(set, file.ConfigKey{Path: v, Value: changes.defaults[v]})
+	}
+	remove := []string{}
+	for _, v := range changes.Renamed {
+		set = append(set, file.ConfigKey{Path: v.To, Value: liveSet[v.From]})
+		remove = append(remove, v.From)
+	}
+
+	merged, err := file.MergeYml(live, set, remove)
+	if err != nil {
+		return err
+	}
+	if err := file.KeepConfig(srv, pluginName, changes.replaced); err != nil {
+		return err
+	}
+	return srv.WriteFile(changes.Path, bytes.NewReader(merged))
+}
+
+// reviewDefaultConfigs shows how the default config of an installed update
+// differs from the live config and merges the new defaults when asked to
+func reviewDefaultConfigs(srv file.Server, job *installJob, changes *configChanges) {
+	if changes == nil {
+		return
+	}
+
+	if !isJSONOutput() {
+		term.Println(Blue(fmt.Sprintf("The default config of %s %s changed compared with %s", job.Plugin.Name, job.Plugin.Version, changes.Path)).Bold())
+		for _, v := range changes.Added {
+			fmt.Fprintln(term.Output, Green(fmt.Sprintf("  + %s: %v", v, changes.defaults[v])))
+		}
+		for _, v := range changes.Removed {
+			fmt.Fprintln(term.Output, Red("  - "+v))
+		}
+		for _, v := range changes.Renamed {
+			fmt.Fprintln(term.Output, Yellow(fmt.Sprintf("  ~ %s -> %s", v.From, v.To)))
+		}
+	}
+
+	if len(changes.Added) == 0 && len(changes.Renamed) == 0 {
+		return
+	}
+	if !mergeDefaults && !confirm(fmt.Sprintf("Merge the new defaults into %s?", changes.Path), false) {
+		return
+	}
+	if err := mergeNewDefaults(srv, job.Plugin.Name, changes); err != nil {
+		term.Println(Red(fmt.Sprintf("Could not merge the new defaults into %s: %s", changes.Path, err.Error())))
+		return
+	}
+	changes.Merged = true
+}
+
+func keySet(keys []file.ConfigKey) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, v := range keys {
+		result[v.Path] = v.Value
+	}
+	return result
+}
+
+func lastKey(p string) string {
+	return p[strings.LastIndex(p, ".")+1:]
+}
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal/gate"
+	. "github.com/logrusorgru/aurora"
+)
+
+// dependencyGraph holds the dependencies that plugins declare in their plugin.yml
+// as recorded in the Bundle Repository, keyed by plugin name
+type dependencyGraph struct {
+	plugins map[string]*api.Plugin
+	// edges points from a plugin to the plugins that must load before it
+	edges map[string][]string
+}
+
+// resolveDependencies walks the depend entries of every plugin in plugins and
+// returns the plugins that must be installed as well. The entries are read
+// from the version that each plugin resolves to, see releaseMetadata. Soft
+// dependencies that will not be installed are reported but do not cause an
+// error
+func resolveDependencies(srv file.Server, plugins map[string]string) (map[string]string, error) {
+	gs := gate.NewGateService("localhost", "8020")
+
+	graph := &dependencyGraph{
+		plugins: map[string]*api.Plugin{},
+		edges:   map[string][]string{},
+	}
+	required := map[string]string{}
+	unresolved := []string{}
+	softMissing := []string{}
+
+	queue := []string{}
+	for k := range plugins {
+		queue = append(queue, k)
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if graph.find(name) != nil {
+			continue
+		}
+		dbpl, err := gs.GetPlugin(&api.Plugin{Name: name})
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve dependencies of %s: %s", name, err.Error())
+		}
+		graph.plugins[dbpl.Name] = dbpl
+
+		metadata, err := releaseMetadata(dbpl, constraintOf(name, plugins, required))
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve dependencies of %s: %s", name, err.Error())
+		}
+		if metadata == nil {
+			continue
+		}
+
+		for _, dep := range metadata.Depend {
+			graph.edges[dbpl.Name] = append(graph.edges[dbpl.Name], dep)
+			if isDependencyPresent(srv, dep, plugins, required) {
+				if isManaged(dep, plugins, required) {
+					queue = append(queue, dep)
+				}
+				continue
+			}
+			if _, err := gs.GetPlugin(&api.Plugin{Name: dep}); err != nil {
+				unresolved = append(unresolved, fmt.Sprintf("%s depends on %s, which is not in the Bundle Repository", dbpl.Name, dep))
+				continue
+			}
+			term.Println(fmt.Sprintf("Adding %s since %s depends on it", Bold(dep), dbpl.Name))
+			required[dep] = withChannel("latest")
+			queue = append(queue, dep)
+		}
+
+		// a plugin that loads before another behaves like a dependency of it
+		for _, after := range metadata.Loadbefore {
+			graph.edges[after] = append(graph.edges[after], dbpl.Name)
+		}
+
+		for _, dep := range metadata.Softdepend {
+			if !isDependencyPresent(srv, dep, plugins, required) {
+				softMissing = append(softMissing, fmt.Sprintf("%s can use %s but it will not be installed", dbpl.Name, dep))
+			}
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("unresolvable dependencies:\n  %s", strings.Join(unresolved, "\n  "))
+	}
+
+	if cycle := graph.findCycle(); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	for _, v := range softMissing {
+		term.Println(Yellow(fmt.Sprintf("Missing soft dependency: %s", v)))
+	}
+
+	return required, nil
+}
+
+// releaseMetadata returns what the plugin.yml of the version of a plugin that
+// the constraint resolves to declares. Releases uploaded before their
+// dependencies were recorded fall back to those of the latest upload
+func releaseMetadata(plugin *api.Plugin, constraint string) (*api.PluginMetadata, error) {
+	resolved, err := resolveVersion(plugin, constraint)
+	if err != nil {
+		return nil, err
+	}
+	if resolved == plugin.Version {
+		return plugin.Metadata, nil
+	}
+
+	gs := gate.NewGateService("localhost", "8020")
+	releases, err := gs.GetReleases(&api.Plugin{Id: plugin.Id})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range releases.Releases {
+		if v.Version == resolved && v.Metadata != nil {
+			return v.Metadata, nil
+		}
+	}
+	return plugin.Metadata, nil
+}
+
+// constraintOf returns the version constraint that a plugin is installed with
+func constraintOf(name string, plugins, required map[string]string) string {
+	if k, ok := findPluginKey(plugins, name); ok {
+		return plugins[k]
+	}
+	if k, ok := findPluginKey(required, name); ok {
+		return required[k]
+	}
+	return "latest"
+}
+
+// isDependencyPresent reports whether a dependency is already going to be
+// installed or is installed in the plugins folder
+func isDependencyPresent(srv file.Server, dep string, plugins, required map[string]string) bool {
+	if _, ok := findPluginKey(plugins, dep); ok {
+		return true
+	}
+	if _, ok := findPluginKey(required, dep); ok {
+		return true
+	}
+	_, err := file.GetPluginYml(srv, dep)
+	return err == nil
+}
+
+// isManaged reports whether Bundle installs the plugin. Dependencies of jars
+// that were put in the plugins folder by hand are not followed
+func isManaged(name string, plugins, required map[string]string) bool {
+	if _, ok := findPluginKey(plugins, name); ok {
+		return true
+	}
+	_, ok := findPluginKey(required, name)
+	return ok
+}
+
+func (g *dependencyGraph) find(name string) *api.Plugin {
+	for k, v := range g.plugins {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// findCycle returns the plugins that form a cycle of hard dependencies and
+// load orders, or nil if the graph can be loaded in some order
+func (g *dependencyGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	path := []string{}
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		key := strings.ToLower(name)
+		switch state[key] {
+		case visiting:
+			for i, v := range path {
+				if strings.EqualFold(v, name) {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		state[key] = visiting
+		path = append(path, name)
+		for k, deps := range g.edges {
+			if !strings.EqualFold(k, name) {
+				continue
+			}
+			for _, dep := range deps {
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[key] = visited
+		return nil
+	}
+
+	for k := range g.edges {
+		if cycle := visit(k); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
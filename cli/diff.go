@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bennycio/bundle/cli/term"
+	. "github.com/logrusorgru/aurora"
+)
+
+// diffContext is the number of unchanged lines that are shown around a change
+const diffContext = 2
+
+type diffOp int
+
+const (
+	diffSame diffOp = iota
+	diffRemoved
+	diffAdded
+)
+
+type diffLine struct {
+	Op   diffOp
+	Text string
+}
+
+// lineDiff compares two texts line by line using their longest common
+// subsequence
+func lineDiff(from, to string) []diffLine {
+	a := strings.Split(strings.TrimSuffix(from, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(to, "\n"), "\n")
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	result := []diffLine{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, diffLine{diffSame, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, diffLine{diffRemoved, a[i]})
+			i++
+		default:
+			result = append(result, diffLine{diffAdded, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, diffLine{diffRemoved, a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, diffLine{diffAdded, b[j]})
+	}
+	return result
+}
+
+// printDiff prints the changed lines between two texts along with a few lines
+// around them
+func printDiff(fromName, toName, from, to string) {
+	lines := lineDiff(from, to)
+
+	show := make([]bool, len(lines))
+	for i, v := range lines {
+		if v.Op == diffSame {
+			continue
+		}
+		for k := i - diffContext; k <= i+diffContext; k++ {
+			if k >= 0 && k < len(lines) {
+				show[k] = true
+			}
+		}
+	}
+
+	fmt.Fprintln(term.Output, Red("--- "+fromName).Bold())
+	fmt.Fprintln(term.Output, Green("+++ "+toName).Bold())
+	skipped := false
+	for i, v := range lines {
+		if !show[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			fmt.Fprintln(term.Output, Gray(12, "..."))
+			skipped = false
+		}
+		switch v.Op {
+		case diffRemoved:
+			fmt.Fprintln(term.Output, Red("-"+v.Text))
+		case diffAdded:
+			fmt.Fprintln(term.Output, Green("+"+v.Text))
+		default:
+			fmt.Fprintln(term.Output, " "+v.Text)
+		}
+	}
+}
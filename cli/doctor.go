@@ -0,0 +1,369 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal/version"
+	. "github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// quarantineDir holds the jars that doctor took out of the plugins folder
+	quarantineDir = ".bundle/quarantine"
+)
+
+const (
+	problemCorrupt      = "corrupt"
+	problemDuplicate    = "duplicate"
+	problemNameMismatch = "name-mismatch"
+	problemMisnamed     = "misnamed"
+	problemUnmanaged    = "unmanaged"
+	problemMissingDep   = "missing-dependency"
+	problemNotInstalled = "not-installed"
+)
+
+var applyFixes bool
+
+// doctorProblem is something wrong with the plugins folder, along with the
+// fix that doctor suggests for it when there is one
+type doctorProblem struct {
+	Kind    string `json:"kind"`
+	Plugin  string `json:"plugin,omitempty"`
+	Jar     string `json:"jar,omitempty"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+	Fixed   bool   `json:"fixed"`
+	apply   func() error
+}
+
+type doctorResult struct {
+	Problems []*doctorProblem `json:"problems"`
+}
+
+// scannedJar is a jar of the plugins folder and what its plugin.yml says
+type scannedJar struct {
+	File string
+	Yml  file.PluginYml
+	Err  error
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Find problems with the plugins folder and offer to fix them",
+	Long: `Open every jar in the plugins folder and report corrupt jars, duplicate copies of a plugin,
+	jars whose plugin.yml name does not match their file name or bundle.yml entry, plugins that
+	are not in bundle.yml, plugins of bundle.yml that are not installed, and missing hard
+	dependencies. Jars that are taken out of the plugins folder are moved to .bundle/quarantine`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return doctor(file.NewLocalServer(""), applyFixes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&applyFixes, "fix", false, "apply every suggested fix without asking")
+}
+
+// doctor audits the plugins folder of a server and applies the suggested fixes
+// when asked to. It fails when problems remain
+func doctor(srv file.Server, fix bool) error {
+	bu, err := file.GetBundle(srv)
+	if err != nil {
+		return err
+	}
+	plugins := bu.Plugins
+	if plugins == nil {
+		plugins = map[string]string{}
+	}
+
+	jars, err := scanPluginJars(srv)
+	if err != nil {
+		return err
+	}
+
+	bundleChanged := false
+	problems := diagnose(srv, jars, plugins, &bundleChanged)
+
+	fixable := 0
+	for _, v := range problems {
+		if v.apply != nil {
+			fixable++
+		}
+	}
+
+	if !isJSONOutput() {
+		printProblems(problems)
+	}
+
+	if fixable > 0 && (fix || confirm(fmt.Sprintf("Apply the %d suggested fixes?", fixable), false)) {
+		for _, v := range problems {
+			if v.apply == nil {
+				continue
+			}
+			if err := v.apply(); err != nil {
+				term.Println(Red(fmt.Sprintf("Could not fix %s: %s", v.Message, err.Error())))
+				continue
+			}
+			v.Fixed = true
+		}
+		if bundleChanged {
+			if err := file.WritePluginsToBundle(srv, plugins); err != nil {
+				return err
+			}
+		}
+		if !isJSONOutput() {
+			term.Println(Green("Applied fixes, run doctor again to check the result").Bold())
+		}
+	}
+
+	if isJSONOutput() {
+		if err := printJSON(doctorResult{Problems: problems}); err != nil {
+			return err
+		}
+	}
+
+	remaining := 0
+	for _, v := range problems {
+		if !v.Fixed {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%d problems remain", remaining)
+	}
+	return nil
+}
+
+// scanPluginJars opens every jar in the plugins folder
+func scanPluginJars(srv file.Server) ([]scannedJar, error) {
+	names, err := srv.ReadDir("plugins")
+	if err != nil {
+		return nil, err
+	}
+
+	result := []scannedJar{}
+	for _, v := range names {
+		if !strings.HasSuffix(v, ".jar") {
+			continue
+		}
+		jar := scannedJar{File: v}
+		bs, err := file.ReadFile(srv, path.Join("plugins", v))
+		if err == nil {
+			jar.Yml, err = file.ParsePluginYml(bytes.NewReader(bs), int64(len(bs)))
+		}
+		if err == nil && jar.Yml.Name == "" {
+			err = fmt.Errorf("there is no plugin.yml with a name")
+		}
+		jar.Err = err
+		result = append(result, jar)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].File < result[j].File
+	})
+	return result, nil
+}
+
+// diagnose finds the problems of a plugins folder. Fixes that change bundle.yml
+// edit the given plugins and set bundleChanged, the caller writes them
+func diagnose(srv file.Server, jars []scannedJar, plugins map[string]string, bundleChanged *bool) []*doctorProblem {
+	problems := []*doctorProblem{}
+
+	quarantine := func(name string) func() error {
+		return func() error {
+			if err := srv.MkdirAll(quarantineDir); err != nil {
+				return err
+			}
+			return file.Move(srv, path.Join("plugins", name), path.Join(quarantineDir, name))
+		}
+	}
+	rename := func(from, to string) func() error {
+		return func() error {
+			return file.Move(srv, path.Join("plugins", from), path.Join("plugins", to))
+		}
+	}
+
+	groups := map[string][]scannedJar{}
+	order := []string{}
+	for _, v := range jars {
+		if v.Err != nil {
+			problems = append(problems, &doctorProblem{
+				Kind:    problemCorrupt,
+				Jar:     v.File,
+				Message: fmt.Sprintf("plugins/%s cannot be read: %s", v.File, v.Err.Error()),
+				Fix:     "move it to " + quarantineDir,
+				apply:   quarantine(v.File),
+			})
+			continue
+		}
+		key := strings.ToLower(v.Yml.Name)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], v)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			return version.Compare(group[i].Yml.Version, group[j].Yml.Version) > 0
+		})
+		kept := group[0]
+		name := kept.Yml.Name
+		want := name + ".jar"
+
+		if len(group) > 1 {
+			files := []string{}
+			for _, v := range group {
+				files = append(files, fmt.Sprintf("%s (%s)", v.File, v.Yml.Version))
+			}
+			others := group[1:]
+			problems = append(problems, &doctorProblem{
+				Kind:    problemDuplicate,
+				Plugin:  name,
+				Message: fmt.Sprintf("%s is installed more than once: %s", name, strings.Join(files, ", ")),
+				Fix:     fmt.Sprintf("keep %s and move the other copies to %s", kept.File, quarantineDir),
+				apply: func() error {
+					for _, v := range others {
+						if err := quarantine(v.File)(); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			})
+		}
+
+		stem := strings.TrimSuffix(kept.File, ".jar")
+		if bundleKey, ok := findPluginKey(plugins, stem); ok && !strings.EqualFold(stem, name) {
+			if _, taken := findPluginKey(plugins, name); !taken {
+				ver := plugins[bundleKey]
+				from := kept.File
+				problems = append(problems, &doctorProblem{
+					Kind:    problemNameMismatch,
+					Plugin:  name,
+					Jar:     kept.File,
+					Message: fmt.Sprintf("%s in bundle.yml is installed as plugins/%s but its plugin.yml is named %s", bundleKey, kept.File, name),
+					Fix:     fmt.Sprintf("rename the bundle.yml entry to %s and the jar to %s", name, want),
+					apply: func() error {
+						if err := rename(from, want)(); err != nil {
+							return err
+						}
+						delete(plugins, bundleKey)
+						plugins[name] = ver
+						*bundleChanged = true
+						return nil
+					},
+				})
+				continue
+			}
+		}
+
+		if kept.File != want {
+			problems = append(problems, &doctorProblem{
+				Kind:    problemMisnamed,
+				Plugin:  name,
+				Jar:     kept.File,
+				Message: fmt.Sprintf("plugins/%s holds %s, Bundle expects plugins/%s", kept.File, name, want),
+				Fix:     "rename it to " + want,
+				apply:   rename(kept.File, want),
+			})
+		}
+
+		if _, ok := findPluginKey(plugins, name); !ok {
+			ver := kept.Yml.Version
+			if ver == "" {
+				ver = "latest"
+			}
+			problems = append(problems, &doctorProblem{
+				Kind:    problemUnmanaged,
+				Plugin:  name,
+				Jar:     kept.File,
+				Message: fmt.Sprintf("%s %s is not in bundle.yml", name, kept.Yml.Version),
+				Fix:     fmt.Sprintf("add %s: \"%s\" to bundle.yml", name, ver),
+				apply: func() error {
+					plugins[name] = ver
+					*bundleChanged = true
+					return nil
+				},
+			})
+		}
+	}
+
+	missing := map[string][]string{}
+	for _, key := range order {
+		yml := groups[key][0].Yml
+		for _, dep := range yml.Depend {
+			if _, ok := groups[strings.ToLower(dep)]; ok {
+				continue
+			}
+			if _, ok := findPluginKey(plugins, dep); ok {
+				continue
+			}
+			missing[dep] = append(missing[dep], yml.Name)
+		}
+	}
+	deps := []string{}
+	for k := range missing {
+		deps = append(deps, k)
+	}
+	sort.Strings(deps)
+	for _, dep := range deps {
+		dep := dep
+		problems = append(problems, &doctorProblem{
+			Kind:    problemMissingDep,
+			Plugin:  dep,
+			Message: fmt.Sprintf("%s is required by %s but is not installed", dep, strings.Join(missing[dep], ", ")),
+			Fix:     fmt.Sprintf("add %s: \"latest\" to bundle.yml, then install", dep),
+			apply: func() error {
+				plugins[dep] = "latest"
+				*bundleChanged = true
+				return nil
+			},
+		})
+	}
+
+	keys := []string{}
+	for k := range plugins {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := groups[strings.ToLower(k)]; ok {
+			continue
+		}
+		if srv.Exists(path.Join("plugins", k+".jar")) {
+			// reported above as corrupt or as a name mismatch
+			continue
+		}
+		problems = append(problems, &doctorProblem{
+			Kind:    problemNotInstalled,
+			Plugin:  k,
+			Message: fmt.Sprintf("%s is in bundle.yml but is not installed, run install to install it", k),
+		})
+	}
+
+	return problems
+}
+
+func printProblems(problems []*doctorProblem) {
+	if len(problems) == 0 {
+		term.Println(Green("No problems found").Bold())
+		return
+	}
+	term.Println(Yellow(fmt.Sprintf("Found %d problems:", len(problems))).Bold())
+	for _, v := range problems {
+		fmt.Printf("  %s %s\n", Yellow(v.Kind).Bold(), v.Message)
+		if v.Fix != "" {
+			fmt.Printf("    %s %s\n", Gray(12, "fix:"), v.Fix)
+		}
+	}
+}
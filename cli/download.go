@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/cache"
+	"github.com/bennycio/bundle/cli/logger"
+	"github.com/bennycio/bundle/internal/gate"
+)
+
+// downloadAttempts is how many times an interrupted download is resumed
+// before giving up
+const downloadAttempts = 3
+
+// downloadDir holds partially downloaded jars so that interrupted downloads
+// can be resumed by a later install. It sits in the cache folder so that a
+// finished jar can be moved into the cache
+func downloadDir() (string, error) {
+	dir, err := cache.Dir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "downloads")
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// fetchJar returns the path and digest of a plugin jar in the cache, the jar
+// is downloaded into the cache first unless it is already there. Offline
+// installs only use the cache
+func fetchJar(ctx context.Context, pl *api.Plugin, token string, expectedSha string) (string, string, error) {
+	// the name and version also name the partial download
+	if err := cache.CheckRef(pl.Name, pl.Version); err != nil {
+		return "", "", err
+	}
+	if fp, sum, err := cache.Lookup(pl.Name, pl.Version); err == nil {
+		if expectedSha == "" || sum == expectedSha {
+			return fp, sum, nil
+		}
+	}
+	if offline {
+		return "", "", fmt.Errorf("%s %s is %s", pl.Name, pl.Version, cache.ErrNotCached.Error())
+	}
+
+	fp, sum, err := downloadJar(ctx, pl, token, expectedSha)
+	if err != nil {
+		return "", "", err
+	}
+	stored, err := cache.Store(pl.Name, pl.Version, fp, sum)
+	if err != nil {
+		os.Remove(fp)
+		return "", "", err
+	}
+	fp = stored
+	return fp, sum, nil
+}
+
+// downloadJar streams a plugin jar to a local file, resuming from whatever an
+// earlier attempt left behind. The jar is checked against the digest that the
+// repository stores and, when given, the expected digest before the path of the
+// file and its digest are returned. Cancelling the context interrupts the
+// download and keeps what was fetched so far
+func downloadJar(ctx context.Context, pl *api.Plugin, token string, expectedSha string) (string, string, error) {
+	dir, err := downloadDir()
+	if err != nil {
+		return "", "", err
+	}
+	part := filepath.Join(dir, fmt.Sprintf("%s-%s.jar.part", pl.Name, pl.Version))
+
+	// installs running at the same time share the partial file, so a download
+	// claims it by moving it to a file of its own. Only one install can move
+	// it, the others start over in their own file
+	tmp, err := os.CreateTemp(dir, fmt.Sprintf("%s-%s-*.jar.tmp", pl.Name, pl.Version))
+	if err != nil {
+		return "", "", err
+	}
+	tmp.Close()
+	fp := tmp.Name()
+	os.Rename(part, fp)
+
+	done := false
+	defer func() {
+		if done {
+			return
+		}
+		// keep what was fetched for the next install to resume
+		if fi, err := os.Stat(fp); err == nil && fi.Size() > 0 {
+			os.Rename(fp, part)
+		} else {
+			os.Remove(fp)
+		}
+	}()
+
+	var lastErr error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return "", "", ctx.Err()
+		}
+		resumed, repoSha, err := fetchToFile(ctx, pl, token, fp)
+		if ctx.Err() != nil {
+			return "", "", ctx.Err()
+		}
+		if err != nil {
+			lastErr = err
+			logger.ErrLog.Printf("download of %s %s interrupted (attempt %d/%d): %s", pl.Name, pl.Version, attempt, downloadAttempts, err.Error())
+			continue
+		}
+
+		sum, err := hashFile(fp)
+		if err != nil {
+			return "", "", err
+		}
+
+		if (repoSha != "" && sum != repoSha) || (expectedSha != "" && sum != expectedSha) {
+			os.Remove(fp)
+			if resumed {
+				// the partial file may belong to an older upload of this
+				// version, start over from the first byte
+				lastErr = fmt.Errorf("checksum of %s %s does not match", pl.Name, pl.Version)
+				continue
+			}
+			if expectedSha != "" && sum != expectedSha {
+				return "", "", fmt.Errorf("checksum of %s %s does not match bundle.lock", pl.Name, pl.Version)
+			}
+			return "", "", fmt.Errorf("checksum of %s %s does not match the repository", pl.Name, pl.Version)
+		}
+		done = true
+		return fp, sum, nil
+	}
+	return "", "", fmt.Errorf("could not download %s %s: %v", pl.Name, pl.Version, lastErr)
+}
+
+// fetchToFile appends the rest of a jar to a partial file and reports whether
+// an earlier partial download was resumed along with the digest that the
+// repository stores for the jar
+func fetchToFile(ctx context.Context, pl *api.Plugin, token string, fp string) (bool, string, error) {
+	gs := gate.NewGateServiceWithToken("localhost", "8020", token)
+
+	var offset int64
+	if fi, err := os.Stat(fp); err == nil {
+		offset = fi.Size()
+	}
+
+	dl, err := gs.DownloadPlugin(pl, offset)
+	if err != nil && offset > 0 {
+		// the range may not be satisfiable anymore, try the whole jar
+		os.Remove(fp)
+		offset = 0
+		dl, err = gs.DownloadPlugin(pl, 0)
+	}
+	if err != nil {
+		return false, "", err
+	}
+	defer dl.Body.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			dl.Body.Close()
+		case <-done:
+		}
+	}()
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if dl.Offset != offset {
+		if dl.Offset != 0 {
+			return false, "", fmt.Errorf("asked for %s from byte %d but got byte %d", pl.Name, offset, dl.Offset)
+		}
+		// the server ignored the range and sent the whole jar
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		offset = 0
+	}
+
+	fi, err := os.OpenFile(fp, flags, 0644)
+	if err != nil {
+		return false, "", err
+	}
+	defer fi.Close()
+
+	n, err := io.Copy(fi, dl.Body)
+	if err != nil {
+		return offset > 0, dl.Sha256, err
+	}
+	if dl.Length >= 0 && n < dl.Length {
+		return offset > 0, dl.Sha256, io.ErrUnexpectedEOF
+	}
+	return offset > 0, dl.Sha256, nil
+}
+
+func hashFile(fp string) (string, error) {
+	fi, err := os.Open(fp)
+	if err != nil {
+		return "", err
+	}
+	defer fi.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, fi); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,41 @@
+package file
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	_ "embed"
+)
+
+const (
+	EulaFileName       = "eula.txt"
+	PropertiesFileName = "server.properties"
+	StartScriptName    = "start.sh"
+	StartBatchName     = "start.bat"
+)
+
+//go:embed server.properties
+var ServerProperties string
+
+// WriteEula records whether the Minecraft EULA was accepted, the server does
+// not start until it is
+func WriteEula(srv Server, accepted bool) error {
+	content := fmt.Sprintf("#By changing the setting below to TRUE you are indicating your agreement to our EULA (https://account.mojang.com/documents/minecraft_eula).\n#%s\neula=%t\n", time.Now().Format(time.UnixDate), accepted)
+	return srv.WriteFile(EulaFileName, strings.NewReader(content))
+}
+
+// WriteServerProperties writes the default server.properties
+func WriteServerProperties(srv Server) error {
+	return srv.WriteFile(PropertiesFileName, strings.NewReader(ServerProperties))
+}
+
+// WriteStartScripts writes a shell script and a batch file that start the
+// server jar with the given amount of memory, such as 2G
+func WriteStartScripts(srv Server, jar, memory string) error {
+	cmd := fmt.Sprintf("java -Xms%s -Xmx%s -jar %s nogui", memory, memory, jar)
+	if err := srv.WriteFile(StartScriptName, strings.NewReader("#!/bin/sh\ncd \"$(dirname \"$0\")\"\n"+cmd+"\n")); err != nil {
+		return err
+	}
+	return srv.WriteFile(StartBatchName, strings.NewReader("@echo off\r\ncd /d \"%~dp0\"\r\n"+cmd+"\r\npause\r\n"))
+}
@@ -4,16 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io"
-	"log"
-	"os"
-	"path/filepath"
 	"strings"
 
 	_ "embed"
 
 	"github.com/bennycio/bundle/cli/logger"
-	"github.com/jlaffaye/ftp"
 	"gopkg.in/yaml.v2"
 )
 
@@ -25,150 +20,227 @@ const (
 var BuFile string
 
 type BundleFile struct {
+	Server  *ServerSoftware   `yaml:"Server,omitempty"`
 	Plugins map[string]string `yaml:"Plugins,omitempty"`
+	// Configs maps a config file on the server, such as
+	// plugins/Essentials/config.yml, to the template it is rendered from
+	Configs map[string]string `yaml:"Configs,omitempty"`
+	// Variables are substituted for ${NAME} in config templates
+	Variables    map[string]string      `yaml:"Variables,omitempty"`
+	Environments map[string]Environment `yaml:"Environments,omitempty"`
 }
 
-func Initialize(path string) error {
-	file, err := os.Create(filepath.Join(path, BuFileName))
-	if err != nil {
-		return err
+// Environment is a group of servers, such as staging, that runs the base
+// plugins of a bundle along with its own. Its plugins are added to the base
+// plugins or override their versions, its server replaces the base server.
+// Its configs and variables are added to the base ones the same way
+type Environment struct {
+	Server    *ServerSoftware   `yaml:"Server,omitempty"`
+	Plugins   map[string]string `yaml:"Plugins,omitempty"`
+	Configs   map[string]string `yaml:"Configs,omitempty"`
+	Variables map[string]string `yaml:"Variables,omitempty"`
+}
+
+// ForEnv returns the bundle that an environment installs, which is the base
+// bundle when the name is empty
+func (b *BundleFile) ForEnv(name string) (*BundleFile, error) {
+	result := &BundleFile{
+		Server:    b.Server,
+		Plugins:   map[string]string{},
+		Configs:   map[string]string{},
+		Variables: map[string]string{"ENV": name},
 	}
-	_, err = file.WriteString(BuFile)
-	if err != nil {
-		return err
+	for k, v := range b.Plugins {
+		result.Plugins[k] = v
+	}
+	for k, v := range b.Configs {
+		result.Configs[k] = v
+	}
+	for k, v := range b.Variables {
+		result.Variables[k] = v
+	}
+	if name == "" {
+		return result, nil
 	}
-	return nil
-}
 
-func IsBundleInitialized(path string) bool {
-	path = findBundle(path)
-	_, err := os.Stat(path)
-	return err == nil
+	key, ok := b.findEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("there is no environment called %s in %s", name, BuFileName)
+	}
+	env := b.Environments[key]
+	if env.Server != nil {
+		result.Server = env.Server
+	}
+	for k, v := range env.Plugins {
+		for base := range result.Plugins {
+			if strings.EqualFold(base, k) {
+				delete(result.Plugins, base)
+			}
+		}
+		result.Plugins[k] = v
+	}
+	for k, v := range env.Configs {
+		result.Configs[k] = v
+	}
+	for k, v := range env.Variables {
+		result.Variables[k] = v
+	}
+	return result, nil
 }
 
-func getBundleFileBytes(path string) ([]byte, error) {
+// SetPlugin sets the version of a plugin in the base plugins or, when an
+// environment is named, in the plugins of that environment
+func (b *BundleFile) SetPlugin(env, pluginName, version string) error {
+	plugins := b.Plugins
+	if env != "" {
+		key, ok := b.findEnv(env)
+		if !ok {
+			return fmt.Errorf("there is no environment called %s in %s", env, BuFileName)
+		}
+		e := b.Environments[key]
+		if e.Plugins == nil {
+			e.Plugins = map[string]string{}
+		}
+		b.Environments[key] = e
+		plugins = e.Plugins
+	} else if plugins == nil {
+		b.Plugins = map[string]string{}
+		plugins = b.Plugins
+	}
 
-	if !IsBundleInitialized(path) {
-		return nil, errors.New("bundle file does not exist at current directory")
+	for k := range plugins {
+		if strings.EqualFold(k, pluginName) {
+			delete(plugins, k)
+		}
 	}
-	path = findBundle(path)
+	plugins[pluginName] = version
+	return nil
+}
 
-	fi, err := os.Open(path)
-	if err != nil {
-		return nil, err
+// FindPlugin returns the key that a plugin is listed under, ignoring case, in
+// the base plugins or, when an environment is named, in the plugins of that
+// environment. The plugins that an environment gets from the base plugins are
+// not its own, so they cannot be found there
+func (b *BundleFile) FindPlugin(env, pluginName string) (string, error) {
+	plugins := b.Plugins
+	if env != "" {
+		key, ok := b.findEnv(env)
+		if !ok {
+			return "", fmt.Errorf("there is no environment called %s in %s", env, BuFileName)
+		}
+		plugins = b.Environments[key].Plugins
 	}
+	for k := range plugins {
+		if strings.EqualFold(k, pluginName) {
+			return k, nil
+		}
+	}
+	if env != "" {
+		for k := range b.Plugins {
+			if strings.EqualFold(k, pluginName) {
+				return "", fmt.Errorf("%s is one of the base plugins, remove it without an environment", k)
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not in %s", pluginName, BuFileName)
+}
 
-	bs := &bytes.Buffer{}
-	_, err = io.Copy(bs, fi)
+// RemovePlugin removes a plugin from the base plugins or, when an environment
+// is named, from the plugins of that environment and returns the key that it
+// was listed under
+func (b *BundleFile) RemovePlugin(env, pluginName string) (string, error) {
+	key, err := b.FindPlugin(env, pluginName)
 	if err != nil {
-		logger.ErrLog.Print(err.Error())
+		return "", err
+	}
+	if env == "" {
+		delete(b.Plugins, key)
+		return key, nil
 	}
-	return bs.Bytes(), nil
+	envKey, _ := b.findEnv(env)
+	delete(b.Environments[envKey].Plugins, key)
+	return key, nil
 }
 
-func GetBundle(path string) (*BundleFile, error) {
-
-	fileBytes, err := getBundleFileBytes(path)
-
-	if err != nil {
-		return nil, err
+func (b *BundleFile) findEnv(name string) (string, bool) {
+	for k := range b.Environments {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
 	}
-	result := &BundleFile{}
+	return "", false
+}
 
-	err = yaml.Unmarshal(fileBytes, result)
+// ServerSoftware is the server jar that runs the plugins of a bundle. Its
+// version accepts the same constraints as the versions of plugins
+type ServerSoftware struct {
+	Name    string `yaml:"Name"`
+	Version string `yaml:"Version"`
+	Jar     string `yaml:"Jar,omitempty"`
+}
 
-	if err != nil {
-		logger.ErrLog.Print(err.Error())
+// JarName is the file name of the server jar, server.jar unless bundle.yml
+// says otherwise
+func (s *ServerSoftware) JarName() string {
+	if s.Jar == "" {
+		return "server.jar"
 	}
+	return s.Jar
+}
 
-	return result, nil
+func Initialize(srv Server) error {
+	return srv.WriteFile(BuFileName, strings.NewReader(BuFile))
 }
 
-func GetBundleFtp(conn *ftp.ServerConn) (BundleFile, error) {
-	resp, err := conn.Retr("bundle.yml")
-	if err != nil {
-		return BundleFile{}, err
-	}
-	defer resp.Close()
-	result := &BundleFile{}
+func IsBundleInitialized(srv Server) bool {
+	return srv.Exists(BuFileName)
+}
 
-	buf := &bytes.Buffer{}
+func GetBundle(srv Server) (*BundleFile, error) {
 
-	_, err = io.Copy(buf, resp)
-	if err != nil {
-		return BundleFile{}, err
+	if !IsBundleInitialized(srv) {
+		return nil, errors.New("bundle file does not exist at current directory")
 	}
 
-	err = yaml.Unmarshal(buf.Bytes(), result)
+	fileBytes, err := ReadFile(srv, BuFileName)
 	if err != nil {
-		return BundleFile{}, err
+		return nil, err
 	}
-	return *result, nil
-}
+	result := &BundleFile{}
 
-func WritePluginsToBundle(plugins map[string]string, path string) error {
-	bundle, err := GetBundle(path)
-	if err != nil {
-		return err
-	}
-	bundle.Plugins = plugins
-	path = findBundle(path)
+	err = yaml.Unmarshal(fileBytes, result)
 
-	currentBundleFile, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		return err
-	}
-	currentBundleFile.Truncate(0)
-	newFileBytes, err := yaml.Marshal(bundle)
 	if err != nil {
-		return err
-	}
-	_, err = currentBundleFile.Write(newFileBytes)
-	if err != nil {
-		return err
+		logger.ErrLog.Print(err.Error())
 	}
-	return nil
+
+	return result, nil
 }
 
-func WritePluginsToBundleFtp(conn *ftp.ServerConn, plugins map[string]string) error {
-	bundle, err := GetBundleFtp(conn)
+func WritePluginsToBundle(srv Server, plugins map[string]string) error {
+	bundle, err := GetBundle(srv)
 	if err != nil {
 		return err
 	}
 	bundle.Plugins = plugins
+	return WriteBundle(srv, bundle)
+}
 
-	conn.Delete(BuFileName)
-	newFileBytes, err := yaml.Marshal(bundle)
+// WriteServerToBundle pins the server software of a bundle
+func WriteServerToBundle(srv Server, server *ServerSoftware) error {
+	bundle, err := GetBundle(srv)
 	if err != nil {
 		return err
 	}
-
-	pr, pw := io.Pipe()
-
-	go func() {
-		defer pw.Close()
-		_, err := pw.Write(newFileBytes)
-		if err != nil {
-			fmt.Printf("error occurred: %s\n", err.Error())
-			return
-		}
-	}()
-
-	if err := conn.Stor(BuFileName, pr); err != nil {
-		return err
-	}
-
-	return nil
+	bundle.Server = server
+	return WriteBundle(srv, bundle)
 }
 
-func findBundle(path string) string {
-	result := path
-	wd, err := os.Getwd()
+// WriteBundle replaces bundle.yml
+func WriteBundle(srv Server, bundle *BundleFile) error {
+	newFileBytes, err := yaml.Marshal(bundle)
 	if err != nil {
-		log.Fatal(err.Error())
-	}
-	if path == "" || !strings.HasSuffix(path, BuFileName) {
-		result = filepath.Join(wd, BuFileName)
+		return err
 	}
-	return result
+	return srv.WriteFile(BuFileName, bytes.NewReader(newFileBytes))
 }
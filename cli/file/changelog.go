@@ -0,0 +1,158 @@
+package file
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ErrNoChanges is returned when a changelog has no changes for a version
+var ErrNoChanges = errors.New("no changes for version")
+
+// ChangelogEntry lists the changes of one version of a plugin
+type ChangelogEntry struct {
+	Added   []string `yaml:"Added,omitempty"`
+	Removed []string `yaml:"Removed,omitempty"`
+	Updated []string `yaml:"Updated,omitempty"`
+}
+
+// Empty reports whether the entry lists no changes at all
+func (e *ChangelogEntry) Empty() bool {
+	return len(e.Added) == 0 && len(e.Removed) == 0 && len(e.Updated) == 0
+}
+
+// ReadChangelog reads the changes of a version from a changelog file. A
+// markdown file is read as a Keep a Changelog file, see ParseChangelogMd, any
+// other file must map every version to its changes, such as
+//
+//	1.2.0:
+//	  Added:
+//	    - /home command
+func ReadChangelog(name string, version string) (*ChangelogEntry, error) {
+	bs, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(name), ".md") {
+		entry, err := ParseChangelogMd(string(bs), version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return entry, nil
+	}
+	entries := map[string]*ChangelogEntry{}
+	if err := yaml.UnmarshalStrict(bs, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err.Error())
+	}
+	entry, ok := entries[version]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w %s", name, ErrNoChanges, version)
+	}
+	if entry == nil {
+		entry = &ChangelogEntry{}
+	}
+	return entry, nil
+}
+
+// changelogVersion matches the heading of a version in a Keep a Changelog
+// file, such as ## [1.2.0] - 2021-08-01
+var changelogVersion = regexp.MustCompile(`^##\s+\[?v?([^\]\s]+)\]?`)
+
+// ParseChangelogMd reads the section of a version from a changelog in the Keep
+// a Changelog format. Added and Removed keep their names, every other kind of
+// change, such as Changed or Fixed, is listed as Updated
+func ParseChangelogMd(text string, version string) (*ChangelogEntry, error) {
+	entry := &ChangelogEntry{}
+	found := false
+	inVersion := false
+	var list *[]string
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "## ") {
+			if inVersion {
+				break
+			}
+			m := changelogVersion.FindStringSubmatch(trimmed)
+			inVersion = m != nil && strings.EqualFold(m[1], strings.TrimPrefix(version, "v"))
+			found = found || inVersion
+			list = nil
+			continue
+		}
+		if !inVersion {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "### ") {
+			switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(trimmed, "### "))) {
+			case "added":
+				list = &entry.Added
+			case "removed":
+				list = &entry.Removed
+			default:
+				list = &entry.Updated
+			}
+			continue
+		}
+		if list == nil || trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+			*list = append(*list, strings.TrimSpace(trimmed[2:]))
+		} else if len(*list) > 0 && line != trimmed {
+			// an indented line continues the item above it
+			(*list)[len(*list)-1] += " " + trimmed
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w %s", ErrNoChanges, version)
+	}
+	return entry, nil
+}
+
+// conventionalCommit matches the subject of a conventional commit, such as
+// feat(warps)!: add /home
+var conventionalCommit = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// ParseConventionalCommits builds a changelog from the subjects of conventional
+// commits. feat is listed as Added, fix, perf and refactor as Updated, and
+// revert and remove as Removed. Other commits, such as docs or chore, are left
+// out
+func ParseConventionalCommits(subjects []string) *ChangelogEntry {
+	entry := &ChangelogEntry{}
+	for _, v := range subjects {
+		m := conventionalCommit.FindStringSubmatch(strings.TrimSpace(v))
+		if m == nil {
+			continue
+		}
+		change := m[4]
+		if m[2] != "" {
+			change = m[2] + ": " + change
+		}
+		if m[3] != "" {
+			change += " (breaking)"
+		}
+		switch strings.ToLower(m[1]) {
+		case "feat":
+			entry.Added = append(entry.Added, change)
+		case "fix", "perf", "refactor":
+			entry.Updated = append(entry.Updated, change)
+		case "revert", "remove":
+			entry.Removed = append(entry.Removed, change)
+		}
+	}
+	return entry
+}
@@ -0,0 +1,145 @@
+package file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const keepAChangelog = `# Changelog
+
+## [Unreleased]
+### Added
+- Something not released yet
+
+## [1.2.0] - 2021-08-01
+### Added
+- /home command
+* /sethome command
+### Fixed
+- Teleporting into walls
+  when the chunk was not loaded
+### Changed
+- Faster warps
+### Removed
+- /spawn command
+
+## v1.1.0
+### Security
+- Escape names in messages
+
+## 1.0.0
+### Added
+- First release
+`
+
+func TestParseChangelogMd(t *testing.T) {
+	tests := []struct {
+		version string
+		want    *ChangelogEntry
+		err     bool
+	}{
+		{"1.2.0", &ChangelogEntry{
+			Added:   []string{"/home command", "/sethome command"},
+			Removed: []string{"/spawn command"},
+			Updated: []string{"Teleporting into walls when the chunk was not loaded", "Faster warps"},
+		}, false},
+		{"v1.2.0", &ChangelogEntry{
+			Added:   []string{"/home command", "/sethome command"},
+			Removed: []string{"/spawn command"},
+			Updated: []string{"Teleporting into walls when the chunk was not loaded", "Faster warps"},
+		}, false},
+		{"1.1.0", &ChangelogEntry{Updated: []string{"Escape names in messages"}}, false},
+		{"1.0.0", &ChangelogEntry{Added: []string{"First release"}}, false},
+		{"unreleased", &ChangelogEntry{Added: []string{"Something not released yet"}}, false},
+		{"1.3.0", nil, true},
+		{"1.2", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseChangelogMd(keepAChangelog, tt.version)
+		if tt.err {
+			if !errors.Is(err, ErrNoChanges) {
+				t.Errorf("ParseChangelogMd(%q) returned %v, want ErrNoChanges", tt.version, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseChangelogMd(%q) returned %v", tt.version, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseChangelogMd(%q) = %+v, want %+v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseConventionalCommits(t *testing.T) {
+	tests := []struct {
+		subjects []string
+		want     *ChangelogEntry
+	}{
+		{[]string{"feat: add /home"}, &ChangelogEntry{Added: []string{"add /home"}}},
+		{[]string{"feat(warps): add /warp"}, &ChangelogEntry{Added: []string{"warps: add /warp"}}},
+		{[]string{"feat(warps)!: rename /warp to /go"}, &ChangelogEntry{Added: []string{"warps: rename /warp to /go (breaking)"}}},
+		{[]string{"refactor!: drop the old config"}, &ChangelogEntry{Updated: []string{"drop the old config (breaking)"}}},
+		{[]string{"fix(homes): save on quit", "perf: cache homes"}, &ChangelogEntry{Updated: []string{"homes: save on quit", "cache homes"}}},
+		{[]string{"revert: add /home", "remove: /spawn"}, &ChangelogEntry{Removed: []string{"add /home", "/spawn"}}},
+		{[]string{"Feat: add /back"}, &ChangelogEntry{Added: []string{"add /back"}}},
+		{[]string{"docs: explain /home", "chore: bump deps", "Merge branch 'main'", ""}, &ChangelogEntry{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseConventionalCommits(tt.subjects); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseConventionalCommits(%q) = %+v, want %+v", tt.subjects, got, tt.want)
+		}
+	}
+}
+
+func TestReadChangelog(t *testing.T) {
+	dir := t.TempDir()
+	md := filepath.Join(dir, "CHANGELOG.md")
+	yml := filepath.Join(dir, "changelog.yml")
+	if err := os.WriteFile(md, []byte(keepAChangelog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(yml, []byte("1.2.0:\n  Added:\n    - /home command\n1.1.0:\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bad := filepath.Join(dir, "bad.yml")
+	if err := os.WriteFile(bad, []byte("1.2.0:\n  Fixed:\n    - typo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, version string
+		want          *ChangelogEntry
+		// notExist and noChanges tell which error is wanted, if any
+		notExist, noChanges bool
+	}{
+		{md, "1.0.0", &ChangelogEntry{Added: []string{"First release"}}, false, false},
+		{md, "1.3.0", nil, false, true},
+		{yml, "1.2.0", &ChangelogEntry{Added: []string{"/home command"}}, false, false},
+		{yml, "1.1.0", &ChangelogEntry{}, false, false},
+		{yml, "1.3.0", nil, false, true},
+		{filepath.Join(dir, "missing.md"), "1.0.0", nil, true, false},
+		{bad, "1.2.0", nil, false, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ReadChangelog(tt.name, tt.version)
+		if errors.Is(err, os.ErrNotExist) != tt.notExist || errors.Is(err, ErrNoChanges) != tt.noChanges {
+			t.Errorf("ReadChangelog(%q, %q) returned %v", filepath.Base(tt.name), tt.version, err)
+			continue
+		}
+		if err == nil && tt.want == nil {
+			t.Errorf("ReadChangelog(%q, %q) = %+v, want an error", filepath.Base(tt.name), tt.version, got)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReadChangelog(%q, %q) = %+v, want %+v", filepath.Base(tt.name), tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultConfigName is the config file that a plugin ships in its jar and
+	// copies to its data folder the first time it starts
+	DefaultConfigName = "config.yml"
+)
+
+// ReadDefaultConfig returns the default config.yml at the root of a jar, or
+// nil when the jar has none
+func ReadDefaultConfig(rd io.ReaderAt, size int64) ([]byte, error) {
+	reader, err := zip.NewReader(rd, size)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range reader.File {
+		if v.Name != DefaultConfigName {
+			continue
+		}
+		rc, err := v.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		buf := &bytes.Buffer{}
+		if _, err := buf.ReadFrom(rc); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, nil
+}
+
+// LiveConfigPath is the config.yml that a plugin uses on the server, in the
+// data folder named after its plugin.yml
+func LiveConfigPath(pluginName string) string {
+	return path.Join("plugins", pluginName, DefaultConfigName)
+}
+
+// KeepConfig copies the live config.yml of a plugin into the history store
+// before it is changed
+func KeepConfig(srv Server, pluginName, pluginVersion string) error {
+	dir := path.Join(HistoryDir, pluginName)
+	if err := srv.MkdirAll(dir); err != nil {
+		return err
+	}
+	return copyFile(srv, LiveConfigPath(pluginName), path.Join(dir, pluginVersion+"."+DefaultConfigName))
+}
+
+// ConfigKey is a setting of a yml config, its path joins the keys of the
+// sections that hold it with dots
+type ConfigKey struct {
+	Path  string
+	Value interface{}
+}
+
+// FlattenYml lists every setting of a yml document in the order they appear.
+// Lists count as a single setting
+func FlattenYml(bs []byte) ([]ConfigKey, error) {
+	doc := yaml.MapSlice{}
+	if err := yaml.Unmarshal(bs, &doc); err != nil {
+		return nil, err
+	}
+	result := []ConfigKey{}
+	flattenInto(&result, "", doc)
+	return result, nil
+}
+
+func flattenInto(result *[]ConfigKey, prefix string, section yaml.MapSlice) {
+	for _, v := range section {
+		p := fmt.Sprint(v.Key)
+		if prefix != "" {
+			p = prefix + "." + p
+		}
+		if sub, ok := v.Value.(yaml.MapSlice); ok && len(sub) > 0 {
+			flattenInto(result, p, sub)
+			continue
+		}
+		*result = append(*result, ConfigKey{Path: p, Value: v.Value})
+	}
+}
+
+// MergeYml sets the given settings of a yml document, creating the sections
+// that hold them, and deletes the removed ones along with sections they leave
+// empty. Comments are not kept
+func MergeYml(bs []byte, set []ConfigKey, remove []string) ([]byte, error) {
+	doc := yaml.MapSlice{}
+	if err := yaml.Unmarshal(bs, &doc); err != nil {
+		return nil, err
+	}
+	for _, v := range remove {
+		doc = deleteKey(doc, strings.Split(v, "."))
+	}
+	for _, v := range set {
+		doc = setKey(doc, strings.Split(v.Path, "."), v.Value)
+	}
+	return yaml.Marshal(doc)
+}
+
+func setKey(section yaml.MapSlice, keys []string, value interface{}) yaml.MapSlice {
+	for i, v := range section {
+		if fmt.Sprint(v.Key) != keys[0] {
+			continue
+		}
+		if len(keys) == 1 {
+			section[i].Value = value
+			return section
+		}
+		sub, _ := v.Value.(yaml.MapSlice)
+		section[i].Value = setKey(sub, keys[1:], value)
+		return section
+	}
+	if len(keys) == 1 {
+		return append(section, yaml.MapItem{Key: keys[0], Value: value})
+	}
+	return append(section, yaml.MapItem{Key: keys[0], Value: setKey(yaml.MapSlice{}, keys[1:], value)})
+}
+
+func deleteKey(section yaml.MapSlice, keys []string) yaml.MapSlice {
+	for i, v := range section {
+		if fmt.Sprint(v.Key) != keys[0] {
+			continue
+		}
+		if len(keys) == 1 {
+			return append(section[:i:i], section[i+1:]...)
+		}
+		if sub, ok := v.Value.(yaml.MapSlice); ok {
+			sub = deleteKey(sub, keys[1:])
+			if len(sub) == 0 {
+				return append(section[:i:i], section[i+1:]...)
+			}
+			section[i].Value = sub
+		}
+		return section
+	}
+	return section
+}
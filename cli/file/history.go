@@ -0,0 +1,109 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bennycio/bundle/internal/version"
+)
+
+const (
+	HistoryDir = ".bundle/history"
+)
+
+// KeepJar copies the installed jar of a plugin into the history store so that
+// it can be restored later. Only the newest limit versions of each plugin are
+// kept, a limit below one keeps every version
+func KeepJar(srv Server, pluginName, pluginVersion string, limit int) error {
+	return keepJar(srv, pluginName, pluginVersion, limit, "")
+}
+
+// keepJar keeps the installed jar and prunes the history of the plugin, but
+// never removes the version that is pinned
+func keepJar(srv Server, pluginName, pluginVersion string, limit int, pinned string) error {
+	if pluginVersion == "" {
+		return errors.New("cannot keep a jar without a version")
+	}
+
+	dir := path.Join(HistoryDir, pluginName)
+	if err := srv.MkdirAll(dir); err != nil {
+		return err
+	}
+	if err := copyFile(srv, fmt.Sprintf("plugins/%s.jar", pluginName), path.Join(dir, pluginVersion+".jar")); err != nil {
+		return err
+	}
+
+	if limit < 1 {
+		return nil
+	}
+
+	versions, err := HistoryVersions(srv, pluginName)
+	if err != nil {
+		return err
+	}
+	if len(versions) <= limit {
+		return nil
+	}
+	for _, v := range versions[limit:] {
+		if v == pinned {
+			continue
+		}
+		if err := srv.Remove(path.Join(dir, v+".jar")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HistoryVersions lists the kept versions of a plugin, newest first
+func HistoryVersions(srv Server, pluginName string) ([]string, error) {
+	dir := path.Join(HistoryDir, pluginName)
+	if !srv.Exists(dir) {
+		return []string{}, nil
+	}
+
+	names, err := srv.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, v := range names {
+		if strings.HasSuffix(v, ".jar") {
+			result = append(result, strings.TrimSuffix(v, ".jar"))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return version.Compare(result[i], result[j]) > 0
+	})
+	return result, nil
+}
+
+// RestoreJar replaces the installed jar of a plugin with a kept version
+func RestoreJar(srv Server, pluginName, pluginVersion string) error {
+	return copyFile(srv, path.Join(HistoryDir, pluginName, pluginVersion+".jar"), fmt.Sprintf("plugins/%s.jar", pluginName))
+}
+
+// RollbackJar keeps the installed jar of a plugin, which is currentVersion, and
+// restores a kept version in its place. The restored version stays in the
+// history even when keeping the installed jar goes over the limit
+func RollbackJar(srv Server, pluginName, currentVersion, pluginVersion string, limit int) error {
+	if currentVersion != "" && currentVersion != pluginVersion {
+		if err := keepJar(srv, pluginName, currentVersion, limit, pluginVersion); err != nil {
+			return err
+		}
+	}
+	return RestoreJar(srv, pluginName, pluginVersion)
+}
+
+func copyFile(srv Server, src, dst string) error {
+	bs, err := ReadFile(srv, src)
+	if err != nil {
+		return err
+	}
+	return srv.WriteFile(dst, bytes.NewReader(bs))
+}
@@ -0,0 +1,127 @@
+package file
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	LockFileName = "bundle.lock"
+)
+
+// BundleLock records the exact artifact that was installed for every plugin
+// so that the same bundle.yml always produces the same plugins folder
+type BundleLock struct {
+	Server       *LockedServer           `yaml:"Server,omitempty"`
+	Plugins      map[string]LockedPlugin `yaml:"Plugins,omitempty"`
+	Environments map[string]*BundleLock  `yaml:"Environments,omitempty"`
+}
+
+// ForEnv returns the part of the lock that records what an environment
+// installed, which is the lock itself when the name is empty
+func (l *BundleLock) ForEnv(name string) *BundleLock {
+	if name == "" {
+		return l
+	}
+	if l.Environments == nil {
+		l.Environments = map[string]*BundleLock{}
+	}
+	for k, v := range l.Environments {
+		if strings.EqualFold(k, name) && v != nil {
+			return v
+		}
+	}
+	result := &BundleLock{}
+	l.Environments[name] = result
+	return result
+}
+
+// LockedServer records the server jar that was installed
+type LockedServer struct {
+	Name         string `yaml:"Name" json:"name"`
+	LockedPlugin `yaml:",inline"`
+}
+
+type LockedPlugin struct {
+	Version string `yaml:"Version" json:"version"`
+	URL     string `yaml:"URL" json:"url"`
+	Sha256  string `yaml:"Sha256" json:"sha256"`
+}
+
+// Find returns the locked entry for a plugin, ignoring the case of the name
+func (l *BundleLock) Find(pluginName string) (string, LockedPlugin, bool) {
+	for k, v := range l.Plugins {
+		if strings.EqualFold(k, pluginName) {
+			return k, v, true
+		}
+	}
+	return "", LockedPlugin{}, false
+}
+
+// Set replaces the locked entry for a plugin, ignoring the case of the name
+func (l *BundleLock) Set(pluginName string, locked LockedPlugin) {
+	if l.Plugins == nil {
+		l.Plugins = map[string]LockedPlugin{}
+	}
+	if k, _, ok := l.Find(pluginName); ok {
+		delete(l.Plugins, k)
+	}
+	l.Plugins[pluginName] = locked
+}
+
+func IsLockInitialized(srv Server) bool {
+	return srv.Exists(LockFileName)
+}
+
+func GetLock(srv Server) (*BundleLock, error) {
+	if !IsLockInitialized(srv) {
+		return nil, errors.New("lock file does not exist at current directory")
+	}
+
+	bs, err := ReadFile(srv, LockFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BundleLock{}
+	err = yaml.Unmarshal(bs, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func WriteLock(srv Server, lock *BundleLock) error {
+	bs, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return srv.WriteFile(LockFileName, bytes.NewReader(bs))
+}
+
+// HashJar returns the hex encoded SHA-256 of an installed plugin jar
+func HashJar(srv Server, pluginName string) (string, error) {
+	return HashFile(srv, fmt.Sprintf("plugins/%s.jar", pluginName))
+}
+
+// HashFile returns the hex encoded SHA-256 of a file on a server
+func HashFile(srv Server, name string) (string, error) {
+	rc, err := srv.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,99 @@
+package file
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bennycio/bundle/internal/version"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	MakeFileName = "bundle-make.yml"
+)
+
+// MakeFile is the manifest that bundle publish releases a plugin from. Paths
+// are relative to the folder of the manifest
+type MakeFile struct {
+	// Jar is the path of the plugin jar or a glob that matches exactly one jar,
+	// such as build/libs/*-all.jar
+	Jar       string `yaml:"Jar"`
+	Readme    string `yaml:"Readme,omitempty"`
+	Changelog string `yaml:"Changelog,omitempty"`
+	Thumbnail string `yaml:"Thumbnail,omitempty"`
+	// Category replaces the category of plugin.yml, such as tools or economy
+	Category string `yaml:"Category,omitempty"`
+	// Price makes the plugin premium, in dollars such as 4.99
+	Price   string `yaml:"Price,omitempty"`
+	Channel string `yaml:"Channel,omitempty"`
+
+	dir string
+}
+
+// GetMakeFile reads a publish manifest
+func GetMakeFile(name string) (*MakeFile, error) {
+	bs, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	result := &MakeFile{}
+	if err := yaml.UnmarshalStrict(bs, result); err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err.Error())
+	}
+	if result.Jar == "" {
+		return nil, fmt.Errorf("%s: Jar is required", name)
+	}
+	if result.Channel == "" {
+		result.Channel = version.Stable
+	}
+	channel, ok := version.ParseChannel(result.Channel)
+	if !ok {
+		return nil, fmt.Errorf("%s: Channel must be one of %s", name, strings.Join(version.Channels, ", "))
+	}
+	result.Channel = channel
+	result.dir = filepath.Dir(name)
+	return result, nil
+}
+
+// Path resolves a path of the manifest
+func (m *MakeFile) Path(name string) string {
+	if name == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(m.dir, filepath.FromSlash(name))
+}
+
+// JarPath finds the jar that the manifest points to
+func (m *MakeFile) JarPath() (string, error) {
+	matches, err := filepath.Glob(m.Path(m.Jar))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no jar matches %s", m.Jar)
+	}
+	if len(matches) > 1 {
+		sort.Strings(matches)
+		return "", fmt.Errorf("%s matches more than one jar: %s", m.Jar, strings.Join(matches, ", "))
+	}
+	return matches[0], nil
+}
+
+// PriceCents converts the price of the manifest to cents, 0 when the plugin is
+// free
+func (m *MakeFile) PriceCents() (int32, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(m.Price), "$")
+	if p == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(p, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("Price %s is not an amount of dollars", m.Price)
+	}
+	return int32(math.Round(f * 100)), nil
+}
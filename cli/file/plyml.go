@@ -5,10 +5,8 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"os"
 	"strings"
 
-	"github.com/jlaffaye/ftp"
 	"gopkg.in/yaml.v2"
 )
 
@@ -18,6 +16,9 @@ type PluginYml struct {
 	Description string   `yaml:"description,omitempty"`
 	Category    int32    `yaml:"category,omitempty"`
 	Conflicts   []string `yaml:"conflicts,omitempty"`
+	Depend      []string `yaml:"depend,omitempty"`
+	SoftDepend  []string `yaml:"softdepend,omitempty"`
+	LoadBefore  []string `yaml:"loadbefore,omitempty"`
 }
 
 func ParsePluginYml(rd io.ReaderAt, size int64) (PluginYml, error) {
@@ -48,45 +49,31 @@ func ParsePluginYml(rd io.ReaderAt, size int64) (PluginYml, error) {
 	return result, nil
 }
 
-func GetPluginYml(pluginName string, conn *ftp.ServerConn) (PluginYml, error) {
-
-	if conn == nil {
-		plfile, err := os.Open(fmt.Sprintf("plugins/%s.jar", pluginName))
-		if err == nil {
-			defer plfile.Close()
-
-			info, err := plfile.Stat()
-			if err != nil {
-				return PluginYml{}, err
-			}
-			p, err := ParsePluginYml(plfile, info.Size())
-			if err != nil {
-				return PluginYml{}, err
-			}
-			return p, nil
-		} else {
-			return PluginYml{}, err
-		}
-	} else {
-		fp := fmt.Sprintf("plugins/%s.jar", pluginName)
-		resp, err := conn.Retr(fp)
-		if err == nil {
-			defer resp.Close()
-			buf := &bytes.Buffer{}
-			size, err := io.Copy(buf, resp)
-			if err != nil {
-				return PluginYml{}, err
-			}
-			rderAt := bytes.NewReader(buf.Bytes())
-			p, err := ParsePluginYml(rderAt, size)
-			if err != nil {
-				return PluginYml{}, err
-			}
-			return p, nil
-		} else {
-
-			return PluginYml{}, err
+// FindJar returns the path of the installed jar of a plugin. The jar is named
+// after the plugin as the repository spells it, which can differ in case from
+// bundle.yml, so the plugins folder is searched ignoring case when there is
+// no exact match
+func FindJar(srv Server, pluginName string) (string, bool) {
+	fp := fmt.Sprintf("plugins/%s.jar", pluginName)
+	if srv.Exists(fp) {
+		return fp, true
+	}
+	names, err := srv.ReadDir("plugins")
+	if err != nil {
+		return "", false
+	}
+	for _, v := range names {
+		if strings.EqualFold(v, pluginName+".jar") {
+			return "plugins/" + v, true
 		}
 	}
+	return "", false
+}
 
+func GetPluginYml(srv Server, pluginName string) (PluginYml, error) {
+	bs, err := ReadFile(srv, fmt.Sprintf("plugins/%s.jar", pluginName))
+	if err != nil {
+		return PluginYml{}, err
+	}
+	return ParsePluginYml(bytes.NewReader(bs), int64(len(bs)))
 }
@@ -0,0 +1,322 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Server is the filesystem of a Minecraft server. Every path is slash
+// separated and relative to the root folder of the server, the folder that
+// holds bundle.yml and the plugins folder
+type Server interface {
+	Open(name string) (io.ReadCloser, error)
+	WriteFile(name string, data io.Reader) error
+	Remove(name string) error
+	// ReadDir returns the names of the entries in a directory
+	ReadDir(name string) ([]string, error)
+	MkdirAll(name string) error
+	Exists(name string) bool
+	Close() error
+}
+
+// ReadFile reads the whole contents of a file on a server
+func ReadFile(srv Server, name string) ([]byte, error) {
+	rc, err := srv.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	buf := &bytes.Buffer{}
+	_, err = io.Copy(buf, rc)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Move moves a file to another path on the same server, replacing the
+// destination if it exists
+func Move(srv Server, src, dst string) error {
+	if err := copyFile(srv, src, dst); err != nil {
+		return err
+	}
+	return srv.Remove(src)
+}
+
+type localServer struct {
+	root string
+}
+
+// NewLocalServer returns the server rooted at the given folder, or at the
+// working directory when root is empty
+func NewLocalServer(root string) Server {
+	return &localServer{root: root}
+}
+
+// IsLocal reports whether a server is on this machine
+func IsLocal(srv Server) bool {
+	_, ok := srv.(*localServer)
+	return ok
+}
+
+func (l *localServer) path(name string) string {
+	root := l.root
+	if root == "" {
+		wd, err := os.Getwd()
+		if err == nil {
+			root = wd
+		}
+	}
+	return filepath.Join(root, filepath.FromSlash(name))
+}
+
+func (l *localServer) Open(name string) (io.ReadCloser, error) {
+	return os.Open(l.path(name))
+}
+
+func (l *localServer) WriteFile(name string, data io.Reader) error {
+	fi, err := os.Create(l.path(name))
+	if err != nil {
+		return err
+	}
+	defer fi.Close()
+	_, err = io.Copy(fi, data)
+	return err
+}
+
+func (l *localServer) Remove(name string) error {
+	return os.Remove(l.path(name))
+}
+
+func (l *localServer) ReadDir(name string) ([]string, error) {
+	entries, err := os.ReadDir(l.path(name))
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, v := range entries {
+		result = append(result, v.Name())
+	}
+	return result, nil
+}
+
+func (l *localServer) MkdirAll(name string) error {
+	return os.MkdirAll(l.path(name), os.ModePerm)
+}
+
+func (l *localServer) Exists(name string) bool {
+	_, err := os.Stat(l.path(name))
+	return err == nil
+}
+
+func (l *localServer) Close() error {
+	return nil
+}
+
+type ftpServer struct {
+	conn *ftp.ServerConn
+}
+
+// DialFtp connects and logs in to an FTP server
+func DialFtp(host, port, username, password string) (Server, error) {
+	conn, err := ftp.Dial(fmt.Sprintf("%s:%s", host, port), ftp.DialWithDisabledEPSV(true), ftp.DialWithDisabledMLSD(true))
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.Login(username, password)
+	if err != nil {
+		conn.Quit()
+		return nil, err
+	}
+	return &ftpServer{conn: conn}, nil
+}
+
+func (f *ftpServer) Open(name string) (io.ReadCloser, error) {
+	return f.conn.Retr(name)
+}
+
+func (f *ftpServer) WriteFile(name string, data io.Reader) error {
+	return f.conn.Stor(name, data)
+}
+
+func (f *ftpServer) Remove(name string) error {
+	return f.conn.Delete(name)
+}
+
+func (f *ftpServer) ReadDir(name string) ([]string, error) {
+	names, err := f.conn.NameList(name)
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, v := range names {
+		result = append(result, path.Base(v))
+	}
+	return result, nil
+}
+
+// MkdirAll creates every directory of a path. FTP servers do not agree on how
+// to report directories that already exist, so the last directory is checked
+// instead of every MKD reply
+func (f *ftpServer) MkdirAll(name string) error {
+	cur := ""
+	for _, v := range strings.Split(path.Clean(name), "/") {
+		cur = path.Join(cur, v)
+		f.conn.MakeDir(cur)
+	}
+	wd, err := f.conn.CurrentDir()
+	if err != nil {
+		return err
+	}
+	if err := f.conn.ChangeDir(name); err != nil {
+		return err
+	}
+	return f.conn.ChangeDir(wd)
+}
+
+func (f *ftpServer) Exists(name string) bool {
+	if _, err := f.conn.FileSize(name); err == nil {
+		return true
+	}
+	names, err := f.conn.NameList(name)
+	return err == nil && len(names) > 0
+}
+
+func (f *ftpServer) Close() error {
+	f.conn.Logout()
+	return f.conn.Quit()
+}
+
+type sftpServer struct {
+	client *sftp.Client
+	ssh    *ssh.Client
+}
+
+// DialSftp connects to an SFTP server with a password, checking its host key
+// with hostKey
+func DialSftp(host, port, username, password string, hostKey ssh.HostKeyCallback) (Server, error) {
+	conf := &ssh.ClientConfig{
+		User:            username,
+		Auth:            []ssh.AuthMethod{ssh.Password(password)},
+		HostKeyCallback: hostKey,
+		Timeout:         30 * time.Second,
+	}
+
+	conn, err := ssh.Dial("tcp", fmt.Sprintf("%s:%s", host, port), conf)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &sftpServer{client: client, ssh: conn}, nil
+}
+
+// KnownHostsCallback checks host keys against ~/.ssh/known_hosts. The key of a
+// host that is not in it yet is shown to trust, and is added to known_hosts
+// if trust accepts it. A host whose key changed is always refused
+func KnownHostsCallback(trust func(host, fingerprint string) bool) (ssh.HostKeyCallback, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	knownHosts := filepath.Join(home, ".ssh", "known_hosts")
+	if err := os.MkdirAll(filepath.Dir(knownHosts), 0700); err != nil {
+		return nil, err
+	}
+	fi, err := os.OpenFile(knownHosts, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	fi.Close()
+
+	check, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := check(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if err == nil || !errors.As(err, &keyErr) {
+			return err
+		}
+		fingerprint := ssh.FingerprintSHA256(key)
+		if len(keyErr.Want) > 0 {
+			return fmt.Errorf("the host key of %s has changed to %s, if this is expected remove its old key from %s", hostname, fingerprint, knownHosts)
+		}
+		if !trust(hostname, fingerprint) {
+			return fmt.Errorf("the host key of %s (%s) is not trusted, add it to %s to connect", hostname, fingerprint, knownHosts)
+		}
+
+		fi, err := os.OpenFile(knownHosts, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer fi.Close()
+		_, err = fmt.Fprintln(fi, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key))
+		return err
+	}, nil
+}
+
+func (s *sftpServer) Open(name string) (io.ReadCloser, error) {
+	return s.client.Open(name)
+}
+
+func (s *sftpServer) WriteFile(name string, data io.Reader) error {
+	fi, err := s.client.Create(name)
+	if err != nil {
+		return err
+	}
+	defer fi.Close()
+	_, err = io.Copy(fi, data)
+	return err
+}
+
+func (s *sftpServer) Remove(name string) error {
+	return s.client.Remove(name)
+}
+
+func (s *sftpServer) ReadDir(name string) ([]string, error) {
+	infos, err := s.client.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, v := range infos {
+		result = append(result, v.Name())
+	}
+	return result, nil
+}
+
+func (s *sftpServer) MkdirAll(name string) error {
+	return s.client.MkdirAll(name)
+}
+
+func (s *sftpServer) Exists(name string) bool {
+	_, err := s.client.Stat(name)
+	return err == nil
+}
+
+func (s *sftpServer) Close() error {
+	s.client.Close()
+	return s.ssh.Close()
+}
@@ -0,0 +1,98 @@
+package file
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DeployedConfigsFile records the config files that were deployed to a
+	// server, so that edits made on the server can be told apart from changes
+	// to their templates
+	DeployedConfigsFile = ".bundle/configs.yml"
+)
+
+// templateVar matches ${NAME} in a config template
+var templateVar = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// RenderTemplate substitutes every ${NAME} of a config template with the
+// variable of that name, falling back to the environment variables of the
+// machine. It fails when a variable is not set anywhere
+func RenderTemplate(template string, vars map[string]string) (string, error) {
+	missing := []string{}
+	result := templateVar.ReplaceAllStringFunc(template, func(m string) string {
+		name := templateVar.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		missing = append(missing, name)
+		return m
+	})
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("%s is not set", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// ContentHash returns the hex encoded SHA-256 of the contents of a file
+func ContentHash(bs []byte) string {
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetDeployedConfigs returns the SHA-256 of every config file as it was last
+// deployed to a server, keyed by its path
+func GetDeployedConfigs(srv Server) (map[string]string, error) {
+	result := map[string]string{}
+	if !srv.Exists(DeployedConfigsFile) {
+		return result, nil
+	}
+	bs, err := ReadFile(srv, DeployedConfigsFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(bs, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WriteDeployedConfigs replaces the record of deployed config files
+func WriteDeployedConfigs(srv Server, deployed map[string]string) error {
+	bs, err := yaml.Marshal(deployed)
+	if err != nil {
+		return err
+	}
+	if err := srv.MkdirAll(".bundle"); err != nil {
+		return err
+	}
+	return srv.WriteFile(DeployedConfigsFile, bytes.NewReader(bs))
+}
+
+// CleanConfigPath makes sure that a config path of bundle.yml stays inside
+// the server folder
+func CleanConfigPath(name string) (string, error) {
+	p := strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "./")
+	if p == "" || strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("%s must be relative to the server folder", name)
+	}
+	for _, v := range strings.Split(p, "/") {
+		if v == ".." {
+			return "", errors.New(name + " must not leave the server folder")
+		}
+	}
+	return p, nil
+}
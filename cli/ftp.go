@@ -1,47 +1,49 @@
 package cli
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
 	"syscall"
-	"time"
 
-	"github.com/bennycio/bundle/api"
 	"github.com/bennycio/bundle/cli/file"
 	"github.com/bennycio/bundle/cli/logger"
 	"github.com/bennycio/bundle/cli/term"
-	"github.com/bennycio/bundle/internal"
 	"github.com/c-bata/go-prompt"
-	"github.com/jlaffaye/ftp"
 	. "github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/ssh"
 	goterm "golang.org/x/term"
 )
 
 type anFtp struct {
 	Name     string
+	Protocol string
 	Host     string
 	Port     string
 	Username string
 	Password string
-	Conn     *ftp.ServerConn
+	Server   file.Server
 }
 
 var theFtp anFtp
 
 var buFileCache file.BundleFile
 
-var curUser *api.User
+var curToken string
+
+// insecureHostKey skips checking the host key of SFTP servers
+var insecureHostKey bool
 
 var connectCommands []prompt.Suggest = []prompt.Suggest{
 	{Text: "help", Description: "See command options"},
-	{Text: "install", Description: "Install/Update plugins"},
+	{Text: "install", Description: "Install/Update plugins (--frozen to install bundle.lock exactly)"},
 	{Text: "init", Description: "Create a new bundle file"},
 	{Text: "remove", Description: "Remove a plugin from bundle file"},
 	{Text: "uninstall", Description: "Delete a plugin"},
@@ -49,20 +51,18 @@ var connectCommands []prompt.Suggest = []prompt.Suggest{
 	{Text: "exit", Description: "Disconnect from FTP instance"},
 	{Text: "list", Description: "List Plugins in Bundle File"},
 	{Text: "add", Description: "Add plugin to bundle file"},
+	{Text: "rollback", Description: "Restore a previous version of a plugin"},
+	{Text: "doctor", Description: "Find problems with the plugins folder (--fix to fix them)"},
+	{Text: "env", Description: "Show or switch the environment of the bundle file (base for none)"},
 }
 
 // testCmd represents the test command
 var ftpCmd = &cobra.Command{
 	Use:   "ftp",
-	Short: "Connect to an instance of an FTP server to run bundle commands from",
+	Short: "Connect to an instance of an FTP or SFTP server to run bundle commands from",
 	RunE: func(cmd *cobra.Command, args []string) error {
 
-		user, err := getCurrentUser()
-		if err != nil {
-			return err
-		}
-
-		curUser = user
+		curToken = currentToken()
 
 		ftps := viper.GetStringMap("FTP")
 
@@ -99,6 +99,11 @@ var ftpCmd = &cobra.Command{
 						return errors.New("invalid ftp config format")
 					}
 
+					if protocol, ok := resultInMap["protocol"].(string); ok {
+						theFtp.Protocol = protocol
+					} else {
+						theFtp.Protocol = "ftp"
+					}
 					if host, ok := resultInMap["host"].(string); ok {
 						theFtp.Host = host
 					} else {
@@ -138,26 +143,35 @@ var ftpCmd = &cobra.Command{
 			}
 		}
 
-		connection, err := ftp.Dial(fmt.Sprintf("%s:%s", theFtp.Host, theFtp.Port), ftp.DialWithDisabledEPSV(true), ftp.DialWithDisabledMLSD(true))
-		if err != nil {
-			return err
+		var srv file.Server
+		var err error
+		if strings.EqualFold(theFtp.Protocol, "sftp") {
+			hostKey := ssh.InsecureIgnoreHostKey()
+			if !insecureHostKey {
+				hostKey, err = file.KnownHostsCallback(func(host, fingerprint string) bool {
+					return confirm(fmt.Sprintf("%s is not a known host, its key fingerprint is %s. Trust it?", host, fingerprint), false)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			srv, err = file.DialSftp(theFtp.Host, theFtp.Port, theFtp.Username, theFtp.Password, hostKey)
+		} else {
+			srv, err = file.DialFtp(theFtp.Host, theFtp.Port, theFtp.Username, theFtp.Password)
 		}
-
-		err = connection.Login(theFtp.Username, theFtp.Password)
 		if err != nil {
 			return err
 		}
 
-		defer connection.Quit()
-		defer connection.Logout()
+		defer srv.Close()
 
 		fmt.Printf("%s\nType 'help' for commands\nType 'exit' to exit\n", fmt.Sprintf("%s %s", Green("Connected To").Bold(), Green(theFtp.Name).Bold()))
 
-		theFtp.Conn = connection
+		theFtp.Server = srv
 
-		bu, err := file.GetBundleFtp(connection)
+		bu, err := file.GetBundle(srv)
 		if err == nil {
-			buFileCache = bu
+			buFileCache = *bu
 		}
 
 		pr := prompt.New(connectedExecutor, connectedCompleter, prompt.OptionPrefix(">> "))
@@ -169,12 +183,14 @@ var ftpCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(ftpCmd)
+	ftpCmd.Flags().StringVarP(&environment, "env", "e", "", "environment of bundle.yml to work with, such as staging")
+	ftpCmd.Flags().BoolVar(&insecureHostKey, "insecure-host-key", false, "connect to an SFTP server without checking its host key")
 }
 
 func connectedCompleter(d prompt.Document) []prompt.Suggest {
 	args := strings.Split(d.TextBeforeCursor(), " ")
 	if len(args) > 1 {
-		if args[0] == "remove" || args[0] == "uninstall" {
+		if args[0] == "remove" || args[0] == "uninstall" || args[0] == "rollback" {
 			s := []prompt.Suggest{}
 
 			for k := range buFileCache.Plugins {
@@ -188,11 +204,10 @@ func connectedCompleter(d prompt.Document) []prompt.Suggest {
 
 func connectedExecutor(s string) {
 
-	conn := theFtp.Conn
+	srv := theFtp.Server
 
 	if s == "exit" {
-		conn.Logout()
-		conn.Quit()
+		srv.Close()
 		os.Exit(1)
 	}
 
@@ -207,60 +222,124 @@ func connectedExecutor(s string) {
 			fmt.Printf("%s: %s\n", Green(v.Text).Bold(), v.Description)
 		}
 	case "install":
-		if len(args) > 1 {
+		base := &file.BundleFile{}
+		if bu, err := file.GetBundle(srv); err == nil {
+			base = bu
+		} else if len(args) < 2 {
+			logger.ErrLog.Print(err.Error())
+			return
+		}
+		buFileCache = *base
+		result, err := base.ForEnv(environment)
+		if err != nil {
+			logger.ErrLog.Print(err.Error())
+			return
+		}
 
-			plsToInstall := map[string]string{}
-			for _, v := range args[1:] {
-				spl := strings.Split(v, "@")
-				if len(spl) < 2 {
-					plsToInstall[spl[0]] = "latest"
-				} else {
-					plsToInstall[spl[0]] = spl[1]
-				}
-			}
-			err := downloadAndInstall(plsToInstall, curUser, conn)
+		if len(args) > 1 && args[1] == "--frozen" {
+			fullLock, err := file.GetLock(srv)
 			if err != nil {
 				logger.ErrLog.Print(err.Error())
 				return
 			}
-		} else {
-			result, err := file.GetBundleFtp(conn)
-			if err != nil {
+			lock := fullLock.ForEnv(environment)
+			if err := checkLockDrift(result.Plugins, result.Server, lock); err != nil {
 				logger.ErrLog.Print(err.Error())
 				return
 			}
-			buFileCache = result
-			err = downloadAndInstall(result.Plugins, curUser, conn)
-			if err != nil {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			summary := downloadAndInstall(ctx, srv, result.Plugins, curToken, lock)
+			if result.Server != nil {
+				report, _ := installServer(ctx, srv, result.Server, curToken, lock.Server, true)
+				summary.Plugins = append([]installReport{report}, summary.Plugins...)
+			}
+			summary.Configs = deployConfigs(srv, result, false)
+			if err := printInstalled(summary); err != nil {
 				logger.ErrLog.Print(err.Error())
-				return
 			}
+			return
 		}
-		fmt.Println(Green("Successfully installed plugins!").Bold())
-	case "init":
 
-		names, err := conn.NameList(".")
+		plsToInstall := result.Plugins
+		if len(args) > 1 {
+			plsToInstall = map[string]string{}
+			for _, v := range args[1:] {
+				spl := strings.SplitN(v, "@", 2)
+				if len(spl) < 2 {
+					plsToInstall[spl[0]] = "latest"
+				} else {
+					plsToInstall[spl[0]] = spl[1]
+				}
+			}
+		}
+		deps, err := resolveDependencies(srv, plsToInstall)
 		if err != nil {
 			logger.ErrLog.Print(err.Error())
 			return
 		}
-		if internal.Contains(names, file.BuFileName) {
-			fmt.Println("bundle file already exists")
-			return
+		if len(deps) > 0 {
+			for k, v := range deps {
+				if key, ok := findPluginKey(result.Plugins, k); ok {
+					k = key
+				}
+				plsToInstall[k] = v
+				result.Plugins[k] = v
+				if err := base.SetPlugin(environment, k, v); err != nil {
+					logger.ErrLog.Print(err.Error())
+				}
+			}
+			if err := file.WriteBundle(srv, base); err != nil {
+				logger.ErrLog.Print(err.Error())
+			}
 		}
 
-		pr, pw := io.Pipe()
+		if err := checkConflicts(srv, plsToInstall); err != nil {
+			logger.ErrLog.Print(err.Error())
+			return
+		}
 
-		go func() {
-			defer pw.Close()
-			_, err := pw.Write([]byte(file.BuFile))
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		// a lock that cannot be read is never replaced, it still pins every
+		// plugin that was installed before
+		fullLock := &file.BundleLock{}
+		if file.IsLockInitialized(srv) {
+			fullLock, err = file.GetLock(srv)
 			if err != nil {
 				logger.ErrLog.Print(err.Error())
 				return
 			}
-		}()
+		}
+
+		summary := downloadAndInstall(ctx, srv, plsToInstall, curToken, nil)
 
-		err = conn.Stor(file.BuFileName, pr)
+		lock := fullLock.ForEnv(environment)
+		if result.Server != nil && len(args) < 2 {
+			report, locked := installServer(ctx, srv, result.Server, curToken, lock.Server, false)
+			summary.Plugins = append([]installReport{report}, summary.Plugins...)
+			if locked != nil {
+				lock.Server = locked
+			}
+		}
+		mergeLock(lock, result.Plugins, summary.Locked, len(args) < 2)
+		if err := file.WriteLock(srv, fullLock); err != nil {
+			logger.ErrLog.Print(err.Error())
+			return
+		}
+		if len(args) < 2 {
+			summary.Configs = deployConfigs(srv, result, false)
+		}
+		if err := printInstalled(summary); err != nil {
+			logger.ErrLog.Print(err.Error())
+		}
+	case "init":
+		if file.IsBundleInitialized(srv) {
+			fmt.Println("bundle file already exists")
+			return
+		}
+
+		err := file.Initialize(srv)
 		if err != nil {
 			logger.ErrLog.Print(err.Error())
 			return
@@ -269,82 +348,93 @@ func connectedExecutor(s string) {
 		fmt.Println(Green("Successfully initialized bundle file!").Bold())
 
 	case "status":
-		bufile, err := file.GetBundleFtp(conn)
+		bufile, err := file.GetBundle(srv)
 		if err != nil {
 			logger.ErrLog.Print(err.Error())
 			return
 		}
-		buFileCache = bufile
-		printStatus(bufile.Plugins, conn)
+		buFileCache = *bufile
+		view, err := bufile.ForEnv(environment)
+		if err != nil {
+			logger.ErrLog.Print(err.Error())
+			return
+		}
+		printStatus(srv, view)
 	case "uninstall":
 		if len(args) < 2 {
 			fmt.Println("Please specify a plugin to remove")
 			return
 		}
-		bu, err := file.GetBundleFtp(conn)
+		bu, err := removePlugins(srv, args[1:], true)
 		if err != nil {
 			logger.ErrLog.Print(err.Error())
-			return
 		}
-		for _, v := range args[1:] {
-			for pl := range bu.Plugins {
-				if strings.EqualFold(v, pl) {
-					err = conn.Delete(fmt.Sprintf("plugins/%s.jar", pl))
-					if err != nil {
-						logger.ErrLog.Print(err.Error())
-						return
-					}
-					time.Sleep(2 * time.Second)
-					delete(bu.Plugins, pl)
-				}
-			}
+		if bu != nil {
+			buFileCache = *bu
 		}
-		err = file.WritePluginsToBundleFtp(conn, bu.Plugins)
-		if err != nil {
-			logger.ErrLog.Print(err.Error())
+	case "remove":
+		if len(args) < 2 {
+			fmt.Println("Please specify a plugin to remove")
 			return
 		}
-		new, err := file.GetBundleFtp(conn)
+		bu, err := removePlugins(srv, args[1:], false)
 		if err != nil {
 			logger.ErrLog.Print(err.Error())
-			return
 		}
-		buFileCache = new
-	case "remove":
+		if bu != nil {
+			buFileCache = *bu
+		}
+	case "rollback":
 		if len(args) < 2 {
-			fmt.Println("Please specify a plugin to remove")
+			fmt.Println("Please specify a plugin to roll back")
 			return
 		}
-		bu, err := file.GetBundleFtp(conn)
-		if err != nil {
+		ver := ""
+		if len(args) > 2 {
+			ver = args[2]
+		}
+		if err := rollback(srv, args[1], ver); err != nil {
 			logger.ErrLog.Print(err.Error())
 			return
 		}
-		for _, v := range args[1:] {
-			for pl := range bu.Plugins {
-				if strings.EqualFold(v, pl) {
-					delete(bu.Plugins, pl)
-				}
-			}
+		if bu, err := file.GetBundle(srv); err == nil {
+			buFileCache = *bu
 		}
-		err = file.WritePluginsToBundleFtp(conn, bu.Plugins)
-		if err != nil {
-			logger.ErrLog.Print(err.Error())
+	case "env":
+		if len(args) < 2 {
+			if environment == "" {
+				fmt.Println("Using the base plugins of the bundle file")
+			} else {
+				fmt.Printf("Using the %s environment\n", environment)
+			}
 			return
 		}
-		new, err := file.GetBundleFtp(conn)
-		if err != nil {
+		name := args[1]
+		if name == "base" {
+			name = ""
+		}
+		if bu, err := file.GetBundle(srv); err == nil {
+			if _, err := bu.ForEnv(name); err != nil {
+				logger.ErrLog.Print(err.Error())
+				return
+			}
+		}
+		environment = name
+		fmt.Println(Green(fmt.Sprintf("Switched to %s", args[1])).Bold())
+	case "doctor":
+		if err := doctor(srv, len(args) > 1 && args[1] == "--fix"); err != nil {
 			logger.ErrLog.Print(err.Error())
-			return
 		}
-		buFileCache = new
+		if bu, err := file.GetBundle(srv); err == nil {
+			buFileCache = *bu
+		}
 	case "list":
-		bu, err := file.GetBundleFtp(conn)
+		bu, err := file.GetBundle(srv)
 		if err != nil {
 			logger.ErrLog.Print(err.Error())
 			return
 		}
-		buFileCache = bu
+		buFileCache = *bu
 		i := 1
 		for k, v := range bu.Plugins {
 			fmt.Printf("%d. %s - %s\n", Green(i), Yellow(k).Bold(), Yellow(v))
@@ -355,33 +445,21 @@ func connectedExecutor(s string) {
 			fmt.Println("Please specify a plugin to add")
 			return
 		}
-		bu, err := file.GetBundleFtp(conn)
+		bu, err := file.GetBundle(srv)
 		if err != nil {
 			logger.ErrLog.Print(err.Error())
 			return
 		}
-		if bu.Plugins == nil {
-			bu.Plugins = map[string]string{}
-		}
-		for _, v := range args[1:] {
-			spl := strings.Split(v, "@")
-			if len(spl) > 1 {
-				bu.Plugins[spl[0]] = spl[1]
-			} else {
-				bu.Plugins[v] = "latest"
-			}
-		}
-		err = file.WritePluginsToBundleFtp(conn, bu.Plugins)
-		if err != nil {
+		if err := addPlugins(bu, args[1:]); err != nil {
 			logger.ErrLog.Print(err.Error())
 			return
 		}
-		new, err := file.GetBundleFtp(conn)
+		err = file.WriteBundle(srv, bu)
 		if err != nil {
 			logger.ErrLog.Print(err.Error())
 			return
 		}
-		buFileCache = new
+		buFileCache = *bu
 	}
 }
 
@@ -389,19 +467,32 @@ func newFtp() error {
 	ftps := viper.GetStringMap("FTP")
 	term.Println("Unique name for this FTP connection: ")
 	theFtp.Name = prompt.Input(">> ", nilCompleter)
-	term.Println("FTP Host: ")
+	term.Println("Protocol (ftp or sftp): ")
+	theFtp.Protocol = strings.ToLower(prompt.Choose(">> ", []string{"ftp", "sftp"}))
+	if theFtp.Protocol != "sftp" {
+		theFtp.Protocol = "ftp"
+	}
+	term.Println("Host: ")
 	theFtp.Host = prompt.Input(">> ", nilCompleter)
-	term.Println("FTP Port: ")
+	term.Println("Port: ")
 	theFtp.Port = prompt.Input(">> ", nilCompleter)
-	term.Println("FTP Username: ")
+	if theFtp.Port == "" {
+		if theFtp.Protocol == "sftp" {
+			theFtp.Port = "22"
+		} else {
+			theFtp.Port = "21"
+		}
+	}
+	term.Println("Username: ")
 	theFtp.Username = prompt.Input(">> ", nilCompleter)
-	term.Println("FTP Password: ")
+	term.Println("Password: ")
 	bytePassword, err := goterm.ReadPassword(syscall.Stdin)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 	theFtp.Password = base64.StdEncoding.EncodeToString(bytePassword)
 	ftps[theFtp.Name] = map[string]string{
+		"Protocol": theFtp.Protocol,
 		"Host":     theFtp.Host,
 		"Port":     theFtp.Port,
 		"Username": theFtp.Username,
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal/gate"
+	. "github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// versionSuffix matches the version that is often part of a jar's file name,
+// as in EssentialsX-2.19.0.jar
+var versionSuffix = regexp.MustCompile(`[-_ ]v?\d+(\.\d+)*.*$`)
+
+// importMatch is a jar of the plugins folder and the repository plugin that it
+// was matched with
+type importMatch struct {
+	Jar     string `json:"jar"`
+	Name    string `json:"name"`
+	Plugin  string `json:"plugin"`
+	Version string `json:"version"`
+	Exact   bool   `json:"exact"`
+}
+
+type importResult struct {
+	Imported  []importMatch `json:"imported"`
+	Unmanaged []string      `json:"unmanaged"`
+}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Add the plugins that are already installed to bundle.yml",
+	Long: `Read the plugin.yml of every jar in the plugins folder, find the plugin in the Bundle Repository
+	and add it to bundle.yml at the installed version. Names that only match approximately are
+	confirmed first. Jars that cannot be found in the repository are listed as unmanaged`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srv := file.NewLocalServer("")
+		fresh := !file.IsBundleInitialized(srv)
+		if fresh {
+			if err := file.Initialize(srv); err != nil {
+				return err
+			}
+		}
+		return importPlugins(srv, fresh)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+}
+
+// importPlugins matches every jar of the plugins folder with the repository and
+// writes the matches to bundle.yml. Plugins that are already in bundle.yml are
+// left alone, a fresh bundle.yml loses the example entry of its template
+func importPlugins(srv file.Server, fresh bool) error {
+	plugins := map[string]string{}
+	if !fresh {
+		bu, err := file.GetBundle(srv)
+		if err != nil {
+			return err
+		}
+		if bu.Plugins != nil {
+			plugins = bu.Plugins
+		}
+	}
+
+	jars, err := scanPluginJars(srv)
+	if err != nil {
+		return err
+	}
+
+	result := importResult{Imported: []importMatch{}, Unmanaged: []string{}}
+
+	for _, v := range jars {
+		if v.Err != nil {
+			result.Unmanaged = append(result.Unmanaged, v.File)
+			continue
+		}
+		if _, ok := findPluginKey(plugins, v.Yml.Name); ok {
+			continue
+		}
+
+		dbpl, exact := matchPlugin(v.Yml.Name, v.File)
+		if dbpl == nil {
+			result.Unmanaged = append(result.Unmanaged, v.File)
+			continue
+		}
+		if _, ok := findPluginKey(plugins, dbpl.Name); ok {
+			continue
+		}
+		if !exact && !confirm(fmt.Sprintf("%s (%s) looks like %s from the Bundle Repository, is it?", v.File, v.Yml.Name, dbpl.Name), true) {
+			result.Unmanaged = append(result.Unmanaged, v.File)
+			continue
+		}
+
+		ver := v.Yml.Version
+		if ver == "" {
+			ver = "latest"
+		}
+		plugins[dbpl.Name] = ver
+		result.Imported = append(result.Imported, importMatch{
+			Jar:     v.File,
+			Name:    v.Yml.Name,
+			Plugin:  dbpl.Name,
+			Version: ver,
+			Exact:   exact,
+		})
+	}
+
+	if len(result.Imported) > 0 {
+		if err := file.WritePluginsToBundle(srv, plugins); err != nil {
+			return err
+		}
+	}
+
+	if isJSONOutput() {
+		return printJSON(result)
+	}
+
+	for _, v := range result.Imported {
+		term.Println(fmt.Sprintf("%s %s %s (%s)", Green("imported").Bold(), v.Plugin, v.Version, v.Jar))
+	}
+	for _, v := range result.Unmanaged {
+		term.Println(fmt.Sprintf("%s %s", Yellow("unmanaged").Bold(), v))
+	}
+	term.Println(Green(fmt.Sprintf("Imported %d plugins into %s", len(result.Imported), file.BuFileName)).Bold())
+	return nil
+}
+
+// matchPlugin finds the repository plugin of a jar by the name in its
+// plugin.yml and by its file name, falling back to a search for names that
+// are close enough. It reports whether the match is exact
+func matchPlugin(name, jarFile string) (*api.Plugin, bool) {
+	gs := gate.NewGateService("localhost", "8020")
+	stem := versionSuffix.ReplaceAllString(strings.TrimSuffix(jarFile, ".jar"), "")
+
+	for _, v := range []string{name, stem} {
+		if v == "" {
+			continue
+		}
+		if dbpl, err := gs.GetPlugin(&api.Plugin{Name: v}); err == nil {
+			return dbpl, strings.EqualFold(dbpl.Name, name)
+		}
+	}
+
+	candidates := []*api.Plugin{}
+	for _, v := range []string{name, stem} {
+		res, err := gs.PaginatePlugins(&api.PaginatePluginsRequest{Page: 1, Count: 10, Search: v})
+		if err == nil {
+			candidates = append(candidates, res...)
+		}
+	}
+
+	var best *api.Plugin
+	bestDist := -1
+	for _, c := range candidates {
+		for _, v := range []string{name, stem} {
+			d := levenshtein(normalizeName(c.Name), normalizeName(v))
+			if bestDist < 0 || d < bestDist {
+				best, bestDist = c, d
+			}
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+
+	limit := len(normalizeName(name)) / 4
+	if limit < 2 {
+		limit = 2
+	}
+	if bestDist > limit {
+		return nil, false
+	}
+	return best, false
+}
+
+// normalizeName lowercases a plugin name and drops everything but letters and
+// digits, so that World-Edit and worldedit compare equal
+func normalizeName(s string) string {
+	b := strings.Builder{}
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// levenshtein is the number of single character edits between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if prev[j-1]+cost < cur[j] {
+				cur[j] = prev[j-1] + cost
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -3,16 +3,39 @@ package cli
 import (
 	"errors"
 	"fmt"
-	"strings"
+	"sort"
+	"time"
 
+	"github.com/alexeyco/simpletable"
 	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/term"
 	"github.com/bennycio/bundle/internal/gate"
-	"github.com/c-bata/go-prompt"
+	"github.com/bennycio/bundle/internal/version"
 	. "github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 )
 
-// searchCmd represents the search command
+type infoResult struct {
+	Plugin    *api.Plugin    `json:"plugin"`
+	Changelog *api.Changelog `json:"changelog,omitempty"`
+	Versions  []releaseInfo  `json:"versions,omitempty"`
+}
+
+// releaseInfo is a version of a plugin as "bundle info --versions" lists it.
+// Versions uploaded before releases were recorded only have a channel
+type releaseInfo struct {
+	Version          string `json:"version"`
+	Channel          string `json:"channel"`
+	Size             int64  `json:"size,omitempty"`
+	Sha256           string `json:"sha256,omitempty"`
+	UploadedAt       int64  `json:"uploadedAt,omitempty"`
+	Uploader         string `json:"uploader,omitempty"`
+	MinecraftVersion string `json:"minecraftVersion,omitempty"`
+	// Yanked is the reason that the version was yanked
+	Yanked string `json:"yanked,omitempty"`
+}
+
+// infoCmd represents the info command
 var infoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Get info on a specific plugin",
@@ -30,17 +53,39 @@ var infoCmd = &cobra.Command{
 			return err
 		}
 
+		var versions []releaseInfo
+		if infoVersions {
+			versions, err = pluginReleases(result)
+			if err != nil {
+				return err
+			}
+		}
+
+		if isJSONOutput() {
+			res := infoResult{Plugin: result, Versions: versions}
+			if ch, err := gs.GetChangelog(&api.Changelog{PluginId: result.Id, Version: result.Version}); err == nil {
+				res.Changelog = ch
+			}
+			return printJSON(res)
+		}
+
 		fmt.Println(Blue("|| -- Plugin Info ------------ ||").Bold())
 
 		fmt.Printf("Name: %s\n", result.Name)
 		fmt.Printf("Author: %s\n", result.Author.Username)
 		fmt.Printf("Description: %s\n", result.Description)
-		fmt.Printf("Current Version: %s\n", result.Version)
+		if result.Version != "" {
+			fmt.Printf("Current Version: %s\n", result.Version)
+		} else {
+			fmt.Println("Current Version: no stable release yet")
+		}
 
-		fmt.Println("Would you like to see recent changes? [Y/n]")
+		if infoVersions {
+			printReleases(versions)
+			return nil
+		}
 
-		cont := prompt.Input(">> ", yesOrNoCompleter)
-		if strings.EqualFold(cont, "y") || strings.EqualFold(cont, "yes") {
+		if result.Version != "" && confirm("Would you like to see recent changes?", true) {
 			ch, err := gs.GetChangelog(&api.Changelog{PluginId: result.Id, Version: result.Version})
 			if err != nil {
 				return err
@@ -63,6 +108,100 @@ var infoCmd = &cobra.Command{
 	},
 }
 
+var infoVersions bool
+
 func init() {
 	rootCmd.AddCommand(infoCmd)
+	infoCmd.Flags().BoolVar(&infoVersions, "versions", false, "list every release of the plugin")
+}
+
+// pluginReleases lists every version of a plugin, newest first, with what the
+// repository recorded when it was uploaded
+func pluginReleases(plugin *api.Plugin) ([]releaseInfo, error) {
+	gs := gate.NewGateService("localhost", "8020")
+
+	releases, err := gs.GetReleases(&api.Plugin{Id: plugin.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []releaseInfo{}
+	seen := map[string]bool{}
+	for _, v := range releases.Releases {
+		r := releaseInfo{
+			Version:          v.Version,
+			Channel:          v.Channel,
+			Size:             v.Size_,
+			Sha256:           v.Sha256,
+			UploadedAt:       v.UploadedAt,
+			MinecraftVersion: v.MinecraftVersion,
+			Yanked:           plugin.Yanked[v.Version],
+		}
+		if v.Uploader != nil {
+			r.Uploader = v.Uploader.Username
+		}
+		result = append(result, r)
+		seen[v.Version] = true
+	}
+
+	channels, _ := availableVersions(plugin)
+	for v, ch := range channels {
+		if v != "" && !seen[v] {
+			result = append(result, releaseInfo{Version: v, Channel: ch, Yanked: plugin.Yanked[v]})
+		}
+	}
+
+	for i := range result {
+		if result[i].Channel == "" {
+			result[i].Channel = version.Stable
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return version.Compare(result[i].Version, result[j].Version) > 0
+	})
+	return result, nil
+}
+
+func printReleases(releases []releaseInfo) {
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Text: "Version"},
+			{Text: "Channel"},
+			{Text: "Minecraft"},
+			{Text: "Size"},
+			{Text: "Uploaded"},
+			{Text: "By"},
+		},
+	}
+
+	for _, v := range releases {
+		ver := v.Version
+		if v.Yanked != "" {
+			ver += " (yanked)"
+		}
+		size, uploaded := "", ""
+		if v.Size > 0 {
+			size = byteSize(v.Size)
+		}
+		if v.UploadedAt > 0 {
+			uploaded = time.Unix(v.UploadedAt, 0).Format("2006-01-02")
+		}
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Text: ver},
+			{Text: v.Channel},
+			{Text: v.MinecraftVersion},
+			{Text: size},
+			{Text: uploaded},
+			{Text: v.Uploader},
+		})
+	}
+
+	table.SetStyle(simpletable.StyleCompactLite)
+	fmt.Println(table.String())
+	for _, v := range releases {
+		if v.Yanked != "" {
+			term.Println(Yellow(fmt.Sprintf("%s was yanked: %s", v.Version, v.Yanked)))
+		}
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/bennycio/bundle/cli/file"
 	"github.com/bennycio/bundle/internal"
@@ -29,7 +30,9 @@ var initCmd = &cobra.Command{
 			return errors.New("invalid path")
 		}
 
-		if file.IsBundleInitialized(path) {
+		srv := file.NewLocalServer(path)
+
+		if file.IsBundleInitialized(srv) {
 			return errors.New("there already exists a bundle.yml at this location")
 		}
 
@@ -37,15 +40,24 @@ var initCmd = &cobra.Command{
 			return errors.New("there is no plugin directory in your current directory")
 		}
 
-		err = file.Initialize(path)
+		err = file.Initialize(srv)
 		if err != nil {
 			return err
 		}
+		if scan {
+			return importPlugins(srv, true)
+		}
+		if isJSONOutput() {
+			return printJSON(map[string]string{"path": filepath.Join(path, file.BuFileName)})
+		}
 		fmt.Println("Created file at path " + path + "/" + file.BuFileName)
 		return nil
 	},
 }
 
+var scan bool
+
 func init() {
 	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().BoolVar(&scan, "scan", false, "add the plugins that are already installed, like bundle import")
 }
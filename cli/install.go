@@ -1,38 +1,48 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
-	"io"
-	"os"
-	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
-	"sync/atomic"
 
 	"github.com/bennycio/bundle/api"
 	"github.com/bennycio/bundle/cli/file"
 	"github.com/bennycio/bundle/cli/logger"
-	"github.com/bennycio/bundle/cli/term"
 	"github.com/bennycio/bundle/internal/gate"
-	"github.com/c-bata/go-prompt"
-	"github.com/jlaffaye/ftp"
+	"github.com/bennycio/bundle/internal/version"
 	. "github.com/logrusorgru/aurora"
-	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
-type downloadedPlugin struct {
-	Plugin *api.Plugin
-	Data   []byte
-}
-
 func init() {
 	rootCmd.AddCommand(installCmd)
 	installCmd.PersistentFlags().BoolVarP(&force, "force", "f", false, "force installation without approval of changes and forcibly updates versions")
+	installCmd.Flags().BoolVar(&frozen, "frozen", false, "install exactly what bundle.lock specifies and fail if it has drifted from bundle.yml")
+	installCmd.Flags().BoolVar(&offline, "offline", false, "install only from the download cache without contacting the Bundle Repository")
+	installCmd.Flags().StringVarP(&environment, "env", "e", "", "environment of bundle.yml to install, such as staging")
+	installCmd.Flags().BoolVar(&mergeDefaults, "merge-defaults", false, "merge the settings that updated plugins added to their default config.yml into the live config.yml without asking")
+	installCmd.Flags().StringVar(&releaseChannel, "channel", "", "release channel to install from when bundle.yml does not name one, stable, beta or alpha")
+	installCmd.Flags().IntVarP(&concurrency, "concurrency", "j", 0, "number of plugins to download at the same time (default from the concurrency setting of your config file)")
 }
 
 var force bool
 
+var frozen bool
+
+var concurrency int
+
+var offline bool
+
+// releaseChannel is the release channel that versions without a channel are
+// resolved from, only stable releases when empty
+var releaseChannel string
+
+// environment is the environment of bundle.yml that commands work with, the
+// base bundle when empty
+var environment string
+
 // installCmd represents the install command
 var installCmd = &cobra.Command{
 	Use:     "install",
@@ -41,10 +51,29 @@ var installCmd = &cobra.Command{
 	Long: `Install plugins from the official Bundle Repository to your Bundle. If no plugins are
 	specified, all plugins listed in bundle.yml will be downloaded. Any arguments to this command
 	will be interpreted as plugins to fetch from the Bundle Repository, add to your bundle.yml, and 
-	download to your plugins folder`,
+	download to your plugins folder. The config templates of bundle.yml are rendered and deployed
+	when the whole bundle is installed. Dependencies are read from the version of each plugin that is
+	installed and are added with the release channel of --channel. Versions uploaded before
+	dependencies were recorded per release use those of the latest upload. Every plugin is listed as installed, skipped or failed once
+	the install is done, and the command exits with a non-zero code if any plugin failed`,
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
-		bundle, err := file.GetBundle("")
+		if releaseChannel != "" {
+			ch, ok := version.ParseChannel(releaseChannel)
+			if !ok {
+				return fmt.Errorf("--channel must be one of %s", strings.Join(version.Channels, ", "))
+			}
+			releaseChannel = ch
+		}
+
+		srv := file.NewLocalServer("")
+
+		base, err := file.GetBundle(srv)
+		if err != nil {
+			return err
+		}
+		bundle, err := base.ForEnv(environment)
 		if err != nil {
 			return err
 		}
@@ -54,37 +83,106 @@ var installCmd = &cobra.Command{
 			bundlePlugins = make(map[string]string)
 		}
 
-		user, err := getCurrentUser()
-		if err != nil {
-			return err
+		token := currentToken()
+
+		if frozen {
+			if len(args) > 0 {
+				return errors.New("plugins cannot be specified when installing with --frozen")
+			}
+			fullLock, err := file.GetLock(srv)
+			if err != nil {
+				return err
+			}
+			lock := fullLock.ForEnv(environment)
+			if err := checkLockDrift(bundlePlugins, bundle.Server, lock); err != nil {
+				return err
+			}
+			summary := downloadAndInstall(cmd.Context(), srv, bundlePlugins, token, lock)
+			if bundle.Server != nil {
+				report, _ := installServer(cmd.Context(), srv, bundle.Server, token, lock.Server, true)
+				summary.Plugins = append([]installReport{report}, summary.Plugins...)
+			}
+			summary.Configs = deployConfigs(srv, bundle, force)
+			return printInstalled(summary)
 		}
 
+		plsToInst := bundlePlugins
 		if len(args) > 0 {
-
-			plsToInst := map[string]string{}
+			plsToInst = map[string]string{}
 			for _, v := range args {
-				spl := strings.Split(v, "@")
+				spl := strings.SplitN(v, "@", 2)
 				if len(spl) < 2 {
 					plsToInst[spl[0]] = "latest"
 				} else {
 					plsToInst[spl[0]] = spl[1]
 				}
 			}
-			if err := downloadAndInstall(plsToInst, user, nil); err != nil {
+		}
+
+		deps := map[string]string{}
+		if !offline {
+			deps, err = resolveDependencies(srv, plsToInst)
+			if err != nil {
 				return err
 			}
-		} else {
-			if err := downloadAndInstall(bundlePlugins, user, nil); err != nil {
+		}
+		if len(deps) > 0 {
+			for k, v := range deps {
+				if key, ok := findPluginKey(bundlePlugins, k); ok {
+					k = key
+				}
+				plsToInst[k] = v
+				bundlePlugins[k] = v
+				if err := base.SetPlugin(environment, k, v); err != nil {
+					return err
+				}
+			}
+			if err := file.WriteBundle(srv, base); err != nil {
 				return err
 			}
 		}
 
-		term.Println(Green("Successfully installed plugins! :)").Bold())
-		return nil
+		if !offline {
+			if err := checkConflicts(srv, plsToInst); err != nil {
+				return err
+			}
+		}
+
+		summary := downloadAndInstall(cmd.Context(), srv, plsToInst, token, nil)
+
+		fullLock := &file.BundleLock{}
+		if file.IsLockInitialized(srv) {
+			fullLock, err = file.GetLock(srv)
+			if err != nil {
+				return err
+			}
+		}
+		lock := fullLock.ForEnv(environment)
+		if bundle.Server != nil && len(args) == 0 {
+			report, locked := installServer(cmd.Context(), srv, bundle.Server, token, lock.Server, false)
+			summary.Plugins = append([]installReport{report}, summary.Plugins...)
+			if locked != nil {
+				lock.Server = locked
+			}
+		}
+		mergeLock(lock, bundlePlugins, summary.Locked, len(args) == 0)
+		if err := file.WriteLock(srv, fullLock); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			summary.Configs = deployConfigs(srv, bundle, force)
+		}
+
+		return printInstalled(summary)
 	},
 }
 
-func changesSinceCurrent(pluginId, pluginName, desiredVersion, currentVersion string) ([]string, error) {
+// changesSinceCurrent prints the changelog of every release newer than the
+// current version that satisfies the constraint and is in a release channel it
+// opts in to, and returns those versions, newest first
+func changesSinceCurrent(pluginId, pluginName, constraint, currentVersion string) ([]string, error) {
+	constraint = withChannel(constraint)
+	_, channel := version.SplitChannel(constraint)
 	gs := gate.NewGateService("localhost", "8020")
 	ch := &api.Changelog{PluginId: pluginId}
 
@@ -93,146 +191,124 @@ func changesSinceCurrent(pluginId, pluginName, desiredVersion, currentVersion st
 		return nil, err
 	}
 
+	changelogs := resp.Changelogs
+	sort.Slice(changelogs, func(i, j int) bool {
+		return version.Compare(changelogs[i].Version, changelogs[j].Version) < 0
+	})
+
 	fmt.Printf("%s %s\n", Blue("Changes Since Last Update"), Blue(pluginName).Bold())
 
 	versionsSinceUpdate := []string{}
-	for _, v := range resp.Changelogs {
-		if versionGreaterThan(v.Version, currentVersion) {
-			if versionGreaterThan(desiredVersion, v.Version) || desiredVersion == v.Version {
-				versionsSinceUpdate = append(versionsSinceUpdate, v.Version)
-				fmt.Println(Yellow(v.Version).Bold())
-				fmt.Println(Green("Added: ").Bold())
-				for _, v := range v.Added {
-					fmt.Printf("  - %s\n", Green(v))
-				}
-				fmt.Println(Red("Removed: ").Bold())
-				for _, v := range v.Removed {
-					fmt.Printf("  - %s\n", Red(v))
-				}
-				fmt.Println(Blue("Updated: ").Bold())
-				for _, v := range v.Updated {
-					fmt.Printf("  - %s\n", Blue(v))
-				}
+	for _, v := range changelogs {
+		if version.Compare(v.Version, currentVersion) > 0 && version.ChannelIncludes(channel, v.Channel) && version.Satisfies(v.Version, constraint) {
+			versionsSinceUpdate = append([]string{v.Version}, versionsSinceUpdate...)
+			fmt.Println(Yellow(v.Version).Bold())
+			fmt.Println(Green("Added: ").Bold())
+			for _, v := range v.Added {
+				fmt.Printf("  - %s\n", Green(v))
+			}
+			fmt.Println(Red("Removed: ").Bold())
+			for _, v := range v.Removed {
+				fmt.Printf("  - %s\n", Red(v))
+			}
+			fmt.Println(Blue("Updated: ").Bold())
+			for _, v := range v.Updated {
+				fmt.Printf("  - %s\n", Blue(v))
 			}
 		}
 	}
 	return versionsSinceUpdate, nil
 }
 
-func downloadAndInstall(plugins map[string]string, user *api.User, conn *ftp.ServerConn) error {
+// keepReplacedJar copies the jar that is about to be replaced into the history
+// store so that it can be rolled back to
+func keepReplacedJar(srv file.Server, pluginName string) {
+	plyml, err := file.GetPluginYml(srv, pluginName)
+	if err != nil {
+		return
+	}
+	if err := file.KeepJar(srv, pluginName, plyml.Version, viper.GetInt("historylimit")); err != nil {
+		logger.ErrLog.Print(err.Error())
+	}
+}
+
+// lockedFromInstalled builds a lock entry for a plugin that was already up to date
+func lockedFromInstalled(srv file.Server, pl *api.Plugin, installedVersion string) (file.LockedPlugin, error) {
 	gs := gate.NewGateService("localhost", "8020")
-	installQueue := make(chan downloadedPlugin)
-	mu := &sync.Mutex{}
-	left := int64(len(plugins))
-	i := 1
-	for k, v := range plugins {
-		go func(index int, pluginName, version string) {
-			defer atomic.AddInt64(&left, -1)
-			pl := &api.Plugin{Name: pluginName}
-			dbpl, err := gs.GetPlugin(pl)
-			if err != nil {
-				logger.ErrLog.Print(err.Error())
-			}
-			pl.Id = dbpl.Id
-			pl.Name = dbpl.Name
-
-			if strings.EqualFold(version, "latest") || version == "" {
-				pl.Version = dbpl.Version
-			} else {
-				pl.Version = version
-			}
-
-			if conn == nil {
-				if plyml, err := file.GetPluginYml(pluginName, nil); err == nil {
-					if plyml.Version == dbpl.Version || plyml.Version == pl.Version {
-						return
-					}
-					mu.Lock()
-					func() {
-						defer mu.Unlock()
-						missedVers, err := changesSinceCurrent(pl.Id, pl.Name, pl.Version, plyml.Version)
-						if err != nil {
-							logger.ErrLog.Print(err.Error())
-						}
-						term.Println(fmt.Sprintf("Which version would you like to update to for the plugin: %s (%d/%d)?\nPress enter for the latest version", pl.Name, index, len(plugins)))
-						resVer := prompt.Choose(">> ", missedVers)
-						if resVer != "" {
-							pl.Version = resVer
-						}
-					}()
-				}
-			} else {
-				if plyml, err := file.GetPluginYml(pluginName, conn); err == nil {
-					if plyml.Version == dbpl.Version || plyml.Version == pl.Version {
-						return
-					}
-					mu.Lock()
-					func() {
-						defer mu.Unlock()
-						missedVers, err := changesSinceCurrent(pl.Id, pl.Name, pl.Version, plyml.Version)
-						if err != nil {
-							logger.ErrLog.Print(err.Error())
-						}
-						term.Println(fmt.Sprintf("Which version would you like to update to for the plugin: %s (%d/%d)?\nPress enter for the latest version", pl.Name, index, len(plugins)))
-						resVer := prompt.Choose(">> ", missedVers)
-						if resVer != "" {
-							pl.Version = resVer
-						}
-					}()
-				}
-			}
+	sum, err := file.HashJar(srv, pl.Name)
+	if err != nil {
+		return file.LockedPlugin{}, err
+	}
+	u, err := gs.PluginDownloadUrl(&api.Plugin{Name: pl.Name, Version: installedVersion})
+	if err != nil {
+		return file.LockedPlugin{}, err
+	}
+	return file.LockedPlugin{
+		Version: installedVersion,
+		URL:     u,
+		Sha256:  sum,
+	}, nil
+}
 
-			bs, err := gs.DownloadPlugin(pl, user)
-			if err != nil {
-				logger.ErrLog.Print(err.Error())
-				return
-			}
-			installQueue <- downloadedPlugin{Plugin: pl, Data: bs}
-		}(i, k, v)
-		i += 1
+// mergeLock records newly installed plugins that are part of the bundle and,
+// when the whole bundle was installed, forgets plugins that were removed from it
+func mergeLock(lock *file.BundleLock, plugins map[string]string, installed map[string]file.LockedPlugin, prune bool) {
+	for k, v := range installed {
+		if _, ok := findPluginKey(plugins, k); ok {
+			lock.Set(k, v)
+		}
 	}
-	for {
-		select {
-		case v := <-installQueue:
-			func() {
-				pb := progressbar.DefaultBytes(int64(len(v.Data)), fmt.Sprintf("Installing %s - %s", v.Plugin.Name, v.Plugin.Version))
-				pr, pw := io.Pipe()
-
-				go func() {
-					defer pw.Close()
-
-					writer := io.MultiWriter(pb, pw)
-					_, err := writer.Write(v.Data)
-					if err != nil {
-						logger.ErrLog.Print(err.Error())
-					}
-				}()
-
-				fp := filepath.Join("plugins", v.Plugin.Name+".jar")
-				if conn == nil {
-					os.Remove(fp)
-					fi, err := os.Create(fp)
-					if err != nil {
-						logger.ErrLog.Print(err.Error())
-					}
-					defer fi.Close()
-					_, err = io.Copy(fi, pr)
-					if err != nil {
-						logger.ErrLog.Print(err.Error())
-					}
-				} else {
-					err := conn.Stor(fp, pr)
-					if err != nil {
-						logger.ErrLog.Print(err.Error())
-					}
-				}
-			}()
-		default:
+	if prune {
+		for k := range lock.Plugins {
+			if _, ok := findPluginKey(plugins, k); !ok {
+				delete(lock.Plugins, k)
+			}
 		}
-		if left < 1 {
-			break
+	}
+}
+
+// forgetLocked removes plugins that are no longer part of the bundle from
+// bundle.lock, or from the lock of the environment when one is named, if
+// there is one
+func forgetLocked(srv file.Server, env string, plugins []string) error {
+	if len(plugins) == 0 || !file.IsLockInitialized(srv) {
+		return nil
+	}
+
+	fullLock, err := file.GetLock(srv)
+	if err != nil {
+		return err
+	}
+	lock := fullLock.ForEnv(env)
+	for _, v := range plugins {
+		if k, _, ok := lock.Find(v); ok {
+			delete(lock.Plugins, k)
 		}
 	}
+	return file.WriteLock(srv, fullLock)
+}
 
+// checkLockDrift makes sure that bundle.lock still describes the server and
+// every plugin in bundle.yml and nothing else
+func checkLockDrift(plugins map[string]string, server *file.ServerSoftware, lock *file.BundleLock) error {
+	drift := checkServerDrift(server, lock)
+	for k, v := range plugins {
+		_, locked, ok := lock.Find(k)
+		if !ok {
+			drift = append(drift, fmt.Sprintf("%s is not in %s", k, file.LockFileName))
+			continue
+		}
+		if !version.Satisfies(locked.Version, v) && v != locked.Version {
+			drift = append(drift, fmt.Sprintf("%s wants version %s but %s has %s", k, v, file.LockFileName, locked.Version))
+		}
+	}
+	for k := range lock.Plugins {
+		if _, ok := findPluginKey(plugins, k); !ok {
+			drift = append(drift, fmt.Sprintf("%s is locked but not in %s", k, file.BuFileName))
+		}
+	}
+	if len(drift) > 0 {
+		return fmt.Errorf("%s is out of date with %s:\n  %s", file.LockFileName, file.BuFileName, strings.Join(drift, "\n  "))
+	}
 	return nil
 }
@@ -0,0 +1,411 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/cache"
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/logger"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal"
+	"github.com/bennycio/bundle/internal/gate"
+	"github.com/bennycio/bundle/internal/version"
+	"github.com/c-bata/go-prompt"
+	. "github.com/logrusorgru/aurora"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/viper"
+)
+
+const (
+	outcomeInstalled = "installed"
+	outcomeSkipped   = "skipped"
+	outcomeFailed    = "failed"
+)
+
+// installReport is what happened to a single plugin during an install
+type installReport struct {
+	Plugin  string `json:"plugin"`
+	Version string `json:"version,omitempty"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+	// Config is how the default config of an update differs from the live one
+	Config *configChanges `json:"config,omitempty"`
+	// Warnings tell that the version was yanked or the plugin is deprecated
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// installSummary is the outcome of installing a set of plugins
+type installSummary struct {
+	Plugins []installReport
+	// Locked holds the lock entry of every plugin that is installed once the
+	// install is done, whether or not it was replaced
+	Locked map[string]file.LockedPlugin
+	// Configs holds the outcome of every config file that was deployed
+	Configs []configReport
+}
+
+// Failed lists the plugins that could not be installed
+func (s *installSummary) Failed() []string {
+	result := []string{}
+	for _, v := range s.Plugins {
+		if v.Outcome == outcomeFailed {
+			result = append(result, v.Plugin)
+		}
+	}
+	for _, v := range s.Configs {
+		if v.Outcome == outcomeFailed {
+			result = append(result, v.Path)
+		}
+	}
+	return result
+}
+
+// installJob is a plugin on its way through the installer
+type installJob struct {
+	Name       string
+	Constraint string
+	Plugin     *api.Plugin
+	// Listed is the plugin as the repository lists it, nil when offline
+	Listed   *api.Plugin
+	Locked   file.LockedPlugin
+	Path     string
+	Sha256   string
+	Err      error
+	Config   *configChanges
+	reported bool
+}
+
+// installConcurrency is the number of plugins that are looked up or downloaded
+// at the same time
+func installConcurrency() int {
+	if concurrency > 0 {
+		return concurrency
+	}
+	if n := viper.GetInt("concurrency"); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// downloadAndInstall installs the given plugins and reports what happened to
+// each of them. When a lock is given, only the exact versions it records are
+// installed and each download must match its checksum. Plugins are looked up
+// and downloaded by a bounded number of workers while every prompt and every
+// write to the server happens one at a time. Cancelling the context stops
+// starting new downloads, plugins that were not installed by then fail
+func downloadAndInstall(ctx context.Context, srv file.Server, plugins map[string]string, token string, lock *file.BundleLock) *installSummary {
+	summary := &installSummary{
+		Plugins: []installReport{},
+		Locked:  map[string]file.LockedPlugin{},
+	}
+
+	jobs := []*installJob{}
+	for k, v := range plugins {
+		jobs = append(jobs, &installJob{Name: k, Constraint: v})
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return strings.ToLower(jobs[i].Name) < strings.ToLower(jobs[j].Name)
+	})
+
+	report := func(job *installJob, outcome, reason string) {
+		job.reported = true
+		r := installReport{Plugin: job.Name, Outcome: outcome, Reason: reason, Config: job.Config}
+		if job.Plugin != nil {
+			r.Plugin = job.Plugin.Name
+			r.Version = job.Plugin.Version
+		}
+		if warnings := releaseWarnings(job.Listed, r.Version); len(warnings) > 0 {
+			r.Warnings = warnings
+		}
+		summary.Plugins = append(summary.Plugins, r)
+	}
+
+	forEachLimit(ctx, len(jobs), installConcurrency(), func(i int) {
+		planInstall(jobs[i], lock)
+	})
+
+	toFetch := []*installJob{}
+	for i, job := range jobs {
+		if job.reported {
+			continue
+		}
+		if job.Err != nil {
+			report(job, outcomeFailed, job.Err.Error())
+			continue
+		}
+		if job.Plugin == nil {
+			continue
+		}
+
+		if lock != nil {
+			if sum, err := file.HashJar(srv, job.Plugin.Name); err == nil && sum == job.Locked.Sha256 {
+				summary.Locked[job.Plugin.Name] = job.Locked
+				report(job, outcomeSkipped, "matches "+file.LockFileName)
+				continue
+			}
+			toFetch = append(toFetch, job)
+			continue
+		}
+
+		if plyml, err := file.GetPluginYml(srv, job.Plugin.Name); err == nil {
+			if isUpToDate(plyml.Version, job.Plugin.Version, job.Constraint) {
+				if entry, err := lockedFromInstalled(srv, job.Plugin, plyml.Version); err == nil {
+					summary.Locked[job.Plugin.Name] = entry
+				}
+				job.Plugin.Version = plyml.Version
+				report(job, outcomeSkipped, "up to date")
+				continue
+			}
+			if isInteractive() && !offline && ctx.Err() == nil {
+				missedVers, err := changesSinceCurrent(job.Plugin.Id, job.Plugin.Name, job.Constraint, plyml.Version)
+				if err != nil {
+					logger.ErrLog.Print(err.Error())
+				}
+				term.Println(fmt.Sprintf("Which version would you like to update to for the plugin: %s (%d/%d)?\nPress enter for %s", job.Plugin.Name, i+1, len(jobs), job.Plugin.Version))
+				resVer := prompt.Choose(">> ", missedVers)
+				if resVer != "" {
+					job.Plugin.Version = resVer
+				}
+			}
+		}
+		toFetch = append(toFetch, job)
+	}
+
+	fetched := make(chan *installJob)
+	go func() {
+		forEachLimit(ctx, len(toFetch), installConcurrency(), func(i int) {
+			job := toFetch[i]
+			job.Path, job.Sha256, job.Err = fetchJar(ctx, job.Plugin, token, job.Locked.Sha256)
+			fetched <- job
+		})
+		close(fetched)
+	}()
+
+	gs := gate.NewGateService("localhost", "8020")
+	for job := range fetched {
+		if job.Err != nil {
+			report(job, outcomeFailed, job.Err.Error())
+			continue
+		}
+		if ctx.Err() != nil {
+			report(job, outcomeFailed, ctx.Err().Error())
+			continue
+		}
+		changes := compareDefaultConfigs(srv, job)
+		if err := installJar(srv, job); err != nil {
+			report(job, outcomeFailed, err.Error())
+			continue
+		}
+		reviewDefaultConfigs(srv, job, changes)
+		job.Config = changes
+
+		u, err := gs.PluginDownloadUrl(job.Plugin)
+		if err != nil {
+			logger.ErrLog.Print(err.Error())
+		}
+		summary.Locked[job.Plugin.Name] = file.LockedPlugin{
+			Version: job.Plugin.Version,
+			URL:     u,
+			Sha256:  job.Sha256,
+		}
+		report(job, outcomeInstalled, "")
+	}
+
+	for _, job := range jobs {
+		if !job.reported {
+			reason := "not installed"
+			if ctx.Err() != nil {
+				reason = ctx.Err().Error()
+			}
+			report(job, outcomeFailed, reason)
+		}
+	}
+
+	sort.Slice(summary.Plugins, func(i, j int) bool {
+		return strings.ToLower(summary.Plugins[i].Plugin) < strings.ToLower(summary.Plugins[j].Plugin)
+	})
+	return summary
+}
+
+// planInstall looks up a plugin and decides which version of it to install
+func planInstall(job *installJob, lock *file.BundleLock) {
+	if offline {
+		planCachedInstall(job, lock)
+		return
+	}
+
+	gs := gate.NewGateService("localhost", "8020")
+
+	dbpl, err := gs.GetPlugin(&api.Plugin{Name: job.Name})
+	if err != nil {
+		job.Err = err
+		return
+	}
+	if dbpl.Type == api.ArtifactType_SERVER_SOFTWARE {
+		job.Err = fmt.Errorf("%s is server software, set it as the Server of %s instead", dbpl.Name, file.BuFileName)
+		return
+	}
+	pl := &api.Plugin{Id: dbpl.Id, Name: dbpl.Name}
+	job.Listed = dbpl
+
+	if lock != nil {
+		_, locked, ok := lock.Find(job.Name)
+		if !ok {
+			job.Err = fmt.Errorf("%s is not in %s", job.Name, file.LockFileName)
+			return
+		}
+		job.Locked = locked
+		pl.Version = locked.Version
+		job.Plugin = pl
+		return
+	}
+
+	resolved, err := resolveVersion(dbpl, job.Constraint)
+	if err != nil {
+		job.Err = err
+		return
+	}
+	pl.Version = resolved
+	job.Plugin = pl
+}
+
+// planCachedInstall decides which cached version of a plugin to install
+// without asking the repository
+func planCachedInstall(job *installJob, lock *file.BundleLock) {
+	name, versions, err := cache.Versions(job.Name)
+	if err != nil {
+		job.Err = err
+		return
+	}
+	pl := &api.Plugin{Name: name}
+
+	if lock != nil {
+		_, locked, ok := lock.Find(job.Name)
+		if !ok {
+			job.Err = fmt.Errorf("%s is not in %s", job.Name, file.LockFileName)
+			return
+		}
+		job.Locked = locked
+		pl.Version = locked.Version
+		job.Plugin = pl
+		return
+	}
+
+	if len(versions) == 0 {
+		job.Err = fmt.Errorf("%s is %s", job.Name, cache.ErrNotCached.Error())
+		return
+	}
+	resolved, err := version.Latest(versions, job.Constraint)
+	if err != nil {
+		if !internal.Contains(versions, job.Constraint) {
+			job.Err = fmt.Errorf("no cached version of %s matches %s", name, job.Constraint)
+			return
+		}
+		resolved = job.Constraint
+	}
+	pl.Version = resolved
+	job.Plugin = pl
+}
+
+// installJar copies a downloaded jar into the plugins folder of the server,
+// keeping the jar that it replaces
+func installJar(srv file.Server, job *installJob) error {
+	jar, err := os.Open(job.Path)
+	if err != nil {
+		return err
+	}
+	defer jar.Close()
+
+	size := int64(-1)
+	if fi, err := jar.Stat(); err == nil {
+		size = fi.Size()
+	}
+	pb := progressbar.DefaultBytes(size, fmt.Sprintf("Installing %s - %s", job.Plugin.Name, job.Plugin.Version))
+
+	keepReplacedJar(srv, job.Plugin.Name)
+	return srv.WriteFile(path.Join("plugins", job.Plugin.Name+".jar"), io.TeeReader(jar, pb))
+}
+
+// forEachLimit calls fn for every index below n with at most limit calls
+// running at once and returns when they are done. Once the context is
+// cancelled no more calls are started
+func forEachLimit(ctx context.Context, n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+type installResult struct {
+	Plugins   []installReport              `json:"plugins"`
+	Installed map[string]file.LockedPlugin `json:"installed"`
+	Configs   []configReport               `json:"configs,omitempty"`
+}
+
+// printInstalled prints the outcome of every plugin and returns an error when
+// any of them could not be installed
+func printInstalled(summary *installSummary) error {
+	if isJSONOutput() {
+		if err := printJSON(installResult{Plugins: summary.Plugins, Installed: summary.Locked, Configs: summary.Configs}); err != nil {
+			return err
+		}
+	} else {
+		for _, v := range summary.Plugins {
+			switch v.Outcome {
+			case outcomeInstalled:
+				term.Println(fmt.Sprintf("%s %s %s", Green("installed").Bold(), v.Plugin, v.Version))
+			case outcomeSkipped:
+				term.Println(fmt.Sprintf("%s %s %s (%s)", Yellow("skipped").Bold(), v.Plugin, v.Version, v.Reason))
+			case outcomeFailed:
+				term.Println(fmt.Sprintf("%s %s %s: %s", Red("failed").Bold(), v.Plugin, v.Version, v.Reason))
+			}
+			for _, w := range v.Warnings {
+				term.Println(Yellow("  Warning: " + w))
+			}
+		}
+		for _, v := range summary.Configs {
+			switch v.Outcome {
+			case outcomeDeployed:
+				term.Println(fmt.Sprintf("%s %s", Green("deployed").Bold(), v.Path))
+			case outcomeSkipped:
+				term.Println(fmt.Sprintf("%s %s (%s)", Yellow("skipped").Bold(), v.Path, v.Reason))
+			case outcomeFailed:
+				term.Println(fmt.Sprintf("%s %s: %s", Red("failed").Bold(), v.Path, v.Reason))
+			}
+		}
+	}
+
+	if failed := summary.Failed(); len(failed) > 0 {
+		return fmt.Errorf("could not install %s", strings.Join(failed, ", "))
+	}
+	if !isJSONOutput() {
+		term.Println(Green("Successfully installed plugins! :)").Bold())
+	}
+	return nil
+}
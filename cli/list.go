@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bennycio/bundle/cli/file"
+	. "github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the plugins in your bundle.yml",
+	Long: `List the plugins in your bundle.yml. With --env the plugins that environment installs are
+	listed, its own plugins along with the base plugins`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srv := file.NewLocalServer("")
+
+		base, err := file.GetBundle(srv)
+		if err != nil {
+			return err
+		}
+		bu, err := base.ForEnv(environment)
+		if err != nil {
+			return err
+		}
+		if isJSONOutput() {
+			return printJSON(bu.Plugins)
+		}
+
+		i := 1
+		for k, v := range bu.Plugins {
+			fmt.Printf("%d. %s - %s\n", Green(i), Yellow(k).Bold(), Yellow(v))
+			i += 1
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().StringVarP(&environment, "env", "e", "", "environment of bundle.yml to list the plugins of, such as staging")
+}
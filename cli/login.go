@@ -1,24 +1,139 @@
 package cli
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/credentials"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal/gate"
+	. "github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 )
 
+var loginScopes []string
+
+var loginUsername string
+
+var passwordStdin bool
+
+// whoami is what whoami prints, the token itself is never printed
+type whoami struct {
+	Username  string   `json:"username"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt int64    `json:"expiresAt"`
+	Store     string   `json:"store"`
+}
+
+// loginCmd represents the login command
 var loginCmd = &cobra.Command{
-	Use:   "signup",
-	Short: "Sign up for Bundle MC, allowing you to upload plugins to the official repository",
+	Use:   "login",
+	Short: "Log in to the Bundle Repository",
+	Long: `Trade your username and password for an API token that is kept in the keyring of your
+	operating system. When there is no keyring the token falls back to a plaintext file that only you
+	can read, set BUNDLE_TOKEN_PASSPHRASE to encrypt that file with a passphrase instead. Your password
+	is never saved. Tokens are scoped, ask for fewer scopes with --scope, and can be revoked with logout.
+	Set BUNDLE_TOKEN to use a token without logging in, for example in CI`,
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		user := &api.User{Username: loginUsername}
+		switch {
+		case passwordStdin:
+			if user.Username == "" {
+				return errors.New("specify the --username to log in with")
+			}
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return err
+			}
+			user.Password = strings.TrimSpace(line)
+		case isInteractive():
+			user = credentialsPrompt()
+		default:
+			return errors.New("use --username and --password-stdin to log in without prompts")
+		}
 
-		_, err := newUser()
+		res, err := login(user, loginScopes)
+		if err != nil {
+			return err
+		}
+		if isJSONOutput() {
+			return printJSON(whoami{Username: res.Username, Scopes: res.Scopes, ExpiresAt: res.ExpiresAt})
+		}
+		term.Println(Green(fmt.Sprintf("Logged in as %s", res.Username)).Bold())
+		return nil
+	},
+}
 
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:          "logout",
+	Short:        "Revoke your API token and forget it",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, store, err := credentials.Load()
+		if err == credentials.ErrNotLoggedIn {
+			term.Println("You are not logged in")
+			return nil
+		}
 		if err != nil {
 			return err
 		}
+
+		gs := gate.NewGateServiceWithToken("localhost", "8020", token)
+		if err := gs.RevokeToken(); err != nil {
+			term.Println(Yellow(fmt.Sprintf("Could not revoke the token: %s", err.Error())))
+		}
+		if store == credentials.StoreEnv {
+			term.Println(Yellow(fmt.Sprintf("The token comes from %s, unset it to stop using it", credentials.EnvToken)))
+			return nil
+		}
+		if err := credentials.Delete(); err != nil {
+			return err
+		}
+		term.Println(Green("Logged out").Bold())
 		return nil
+	},
+}
+
+// whoamiCmd represents the whoami command
+var whoamiCmd = &cobra.Command{
+	Use:          "whoami",
+	Short:        "Show the user that you are logged in as",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, store, err := credentials.Load()
+		if err != nil {
+			return err
+		}
+
+		gs := gate.NewGateServiceWithToken("localhost", "8020", token)
+		res, err := gs.GetToken()
+		if err != nil {
+			return fmt.Errorf("your token is no longer valid, run bundle login: %s", err.Error())
+		}
 
+		if isJSONOutput() {
+			return printJSON(whoami{Username: res.Username, Scopes: res.Scopes, ExpiresAt: res.ExpiresAt, Store: store})
+		}
+		term.Println(fmt.Sprintf("Logged in as %s", Bold(res.Username)))
+		fmt.Printf("  scopes:  %s\n", strings.Join(res.Scopes, ", "))
+		fmt.Printf("  expires: %s\n", time.Unix(res.ExpiresAt, 0).Format(time.RFC1123))
+		fmt.Printf("  stored:  %s\n", store)
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(whoamiCmd)
+	loginCmd.Flags().StringSliceVar(&loginScopes, "scope", nil, fmt.Sprintf("scopes of the token, any of %s (default all of them)", strings.Join(gate.ApiTokenScopes, ", ")))
+	loginCmd.Flags().StringVarP(&loginUsername, "username", "u", "", "username to log in with")
+	loginCmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password from stdin")
 }
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/c-bata/go-prompt"
+)
+
+const (
+	// exitUpdatesPending is the exit code of a command that found plugins
+	// which are not at the version bundle.yml asks for
+	exitUpdatesPending = 2
+)
+
+var outputFormat string
+
+var assumeYes bool
+
+var nonInteractive bool
+
+var errUpdatesPending = errors.New("updates are pending")
+
+// errStatusFailed is returned by status when some plugins could not be looked
+// up, so that it exits with a non-zero code instead of reporting them as up to
+// date
+var errStatusFailed = errors.New("some plugins could not be looked up")
+
+func isJSONOutput() bool {
+	return strings.EqualFold(outputFormat, "json")
+}
+
+// isInteractive reports whether prompts may be shown. JSON output is meant for
+// scripts, so it never prompts either
+func isInteractive() bool {
+	return !assumeYes && !nonInteractive && !isJSONOutput()
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printBundlePlugins prints the plugins of bundle.yml after a change when the
+// output is JSON
+func printBundlePlugins(plugins map[string]string) error {
+	if !isJSONOutput() {
+		return nil
+	}
+	return printJSON(plugins)
+}
+
+// confirm asks a yes or no question, answering def without asking when prompts
+// are disabled
+func confirm(question string, def bool) bool {
+	if !isInteractive() {
+		return def
+	}
+
+	if def {
+		term.Println(question + " [Y/n]")
+	} else {
+		term.Println(question + " [y/N]")
+	}
+	resp := prompt.Input(">> ", yesOrNoCompleter)
+	if resp == "" {
+		return def
+	}
+	return strings.EqualFold(resp, "y") || strings.EqualFold(resp, "yes")
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/cli/uploader"
+	"github.com/bennycio/bundle/internal/gate"
+	. "github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// publishCmd represents the publish command
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish your plugin as specified in bundle-make.yml to the official Bundle Repository",
+	Long: `Reads bundle-make.yml and uploads the jar, README, changelog and thumbnail it points to
+without asking anything, so it can run at the end of a Gradle or CI build. Log in with
+bundle login --scope publish first or set BUNDLE_TOKEN.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		mf, err := file.GetMakeFile(makeFilePath)
+		if err != nil {
+			return err
+		}
+
+		jarPath, err := mf.JarPath()
+		if err != nil {
+			return err
+		}
+
+		plugin, err := pluginFromJar(jarPath)
+		if err != nil {
+			return err
+		}
+
+		if mf.Category != "" {
+			category, err := parseCategory(mf.Category)
+			if err != nil {
+				return err
+			}
+			plugin.Category = category
+		}
+
+		price, err := mf.PriceCents()
+		if err != nil {
+			return err
+		}
+		if price > 0 {
+			plugin.Premium = &api.Premium{Price: price}
+		}
+		plugin.Channel = mf.Channel
+
+		var readme *api.Readme
+		if mf.Readme != "" {
+			bs, err := os.ReadFile(mf.Path(mf.Readme))
+			if err != nil {
+				return err
+			}
+			readme = &api.Readme{Plugin: plugin, Text: string(bs)}
+		}
+
+		var changelog *api.Changelog
+		if mf.Changelog != "" {
+			entry, err := file.ReadChangelog(mf.Path(mf.Changelog), plugin.Version)
+			if err != nil {
+				return err
+			}
+			changelog = &api.Changelog{
+				Version: plugin.Version,
+				Channel: plugin.Channel,
+				Added:   entry.Added,
+				Removed: entry.Removed,
+				Updated: entry.Updated,
+			}
+		}
+
+		if mf.Thumbnail != "" {
+			if _, err := os.Stat(mf.Path(mf.Thumbnail)); err != nil {
+				return err
+			}
+		}
+
+		if publishDryRun {
+			return printPublished(plugin, jarPath, mf, true)
+		}
+
+		token, err := requireToken(gate.ScopePublish)
+		if err != nil {
+			return err
+		}
+
+		jar, err := os.Open(jarPath)
+		if err != nil {
+			return err
+		}
+		defer jar.Close()
+
+		upl := &uploader.Uploader{
+			PluginFile: jar,
+			Plugin:     plugin,
+			Token:      token,
+			Readme:     readme,
+			Changelog:  changelog,
+			Silent:     isJSONOutput(),
+		}
+
+		if mf.Thumbnail != "" {
+			thumbnail, err := os.Open(mf.Path(mf.Thumbnail))
+			if err != nil {
+				return err
+			}
+			defer thumbnail.Close()
+			upl.Thumbnail = thumbnail
+		}
+
+		if err := upl.Upload(); err != nil {
+			return err
+		}
+
+		return printPublished(plugin, jarPath, mf, false)
+	},
+}
+
+var makeFilePath string
+
+var publishDryRun bool
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+	publishCmd.Flags().StringVarP(&makeFilePath, "file", "f", file.MakeFileName, "path of the publish manifest")
+	publishCmd.Flags().BoolVar(&publishDryRun, "dry-run", false, "check the manifest and print what would be published without uploading")
+}
+
+// pluginFromJar reads the plugin.yml of a jar into the plugin to upload
+func pluginFromJar(path string) (*api.Plugin, error) {
+	fi, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fi.Close()
+
+	info, err := fi.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := file.ParsePluginYml(fi, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	if result.Name == "" || result.Version == "" {
+		return nil, errors.New(path + " has no name or version in its plugin.yml")
+	}
+
+	return &api.Plugin{
+		Name:        result.Name,
+		Version:     result.Version,
+		Description: result.Description,
+		Category:    api.Category(result.Category),
+		Metadata: &api.PluginMetadata{
+			Conflicts:  result.Conflicts,
+			Depend:     result.Depend,
+			Softdepend: result.SoftDepend,
+			Loadbefore: result.LoadBefore,
+		},
+	}, nil
+}
+
+// parseCategory accepts the name of a category, such as tools, or its number
+func parseCategory(s string) (api.Category, error) {
+	if v, ok := api.Category_value[strings.ToUpper(strings.TrimSpace(s))]; ok {
+		return api.Category(v), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := api.Category_name[int32(n)]; ok {
+			return api.Category(n), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown category %s", s)
+}
+
+type published struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Channel   string `json:"channel"`
+	Category  string `json:"category"`
+	Price     int32  `json:"price,omitempty"`
+	Jar       string `json:"jar"`
+	Readme    string `json:"readme,omitempty"`
+	Changelog string `json:"changelog,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+	DryRun    bool   `json:"dryRun,omitempty"`
+}
+
+func printPublished(plugin *api.Plugin, jarPath string, mf *file.MakeFile, dryRun bool) error {
+	result := published{
+		Name:      plugin.Name,
+		Version:   plugin.Version,
+		Channel:   plugin.Channel,
+		Category:  strings.ToLower(plugin.Category.String()),
+		Jar:       jarPath,
+		Readme:    mf.Path(mf.Readme),
+		Changelog: mf.Path(mf.Changelog),
+		Thumbnail: mf.Path(mf.Thumbnail),
+		DryRun:    dryRun,
+	}
+	if plugin.Premium != nil {
+		result.Price = plugin.Premium.Price
+	}
+
+	if isJSONOutput() {
+		return printJSON(result)
+	}
+
+	if dryRun {
+		term.Println(Yellow(fmt.Sprintf("Would publish %s %s to the %s channel", result.Name, result.Version, result.Channel)).Bold())
+	} else {
+		term.Println(Green(fmt.Sprintf("Published %s %s to the %s channel! :)", result.Name, result.Version, result.Channel)).Bold())
+	}
+	fmt.Fprintln(term.Output, "  jar:", result.Jar)
+	fmt.Fprintln(term.Output, "  category:", result.Category)
+	if result.Price > 0 {
+		fmt.Fprintf(term.Output, "  price: $%d.%02d\n", result.Price/100, result.Price%100)
+	}
+	for _, v := range [][2]string{{"readme", result.Readme}, {"changelog", result.Changelog}, {"thumbnail", result.Thumbnail}} {
+		if v[1] != "" {
+			fmt.Fprintf(term.Output, "  %s: %s\n", v[0], v[1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/spf13/cobra"
+)
+
+// removeCmd represents the remove command
+var removeCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove plugins from your bundle.yml",
+	Long: `Remove plugins from your bundle.yml while leaving their jars in your plugins folder. Use
+	"bundle uninstall" to delete the jars as well. With --env the plugins are removed from that
+	environment`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please specify a plugin to remove")
+		}
+
+		srv := file.NewLocalServer("")
+
+		bu, err := removePlugins(srv, args, false)
+		if bu != nil {
+			if perr := printEnvPlugins(bu); perr != nil {
+				return perr
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(removeCmd)
+	removeCmd.Flags().StringVarP(&environment, "env", "e", "", "environment of bundle.yml to remove the plugins from, such as staging")
+}
+
+// removePlugins takes plugins out of bundle.yml, out of the environment when
+// one is named, and out of bundle.lock. When uninstall is set their jars are
+// deleted first and a plugin whose jar cannot be deleted stays listed. Every
+// plugin is tried, so bundle.yml and bundle.lock always match what was
+// removed, and the plugins that failed are returned together as one error
+func removePlugins(srv file.Server, pluginNames []string, uninstall bool) (*file.BundleFile, error) {
+	bu, err := file.GetBundle(srv)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	failed := []string{}
+	for _, v := range pluginNames {
+		key, err := bu.FindPlugin(environment, v)
+		if err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		if uninstall {
+			// a jar that is not installed has nothing to delete
+			if fp, ok := file.FindJar(srv, key); ok {
+				if err := srv.Remove(fp); err != nil {
+					failed = append(failed, fmt.Sprintf("could not delete the jar of %s: %s", key, err.Error()))
+					continue
+				}
+			}
+		}
+		if _, err := bu.RemovePlugin(environment, key); err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		removed = append(removed, key)
+	}
+
+	if len(removed) > 0 {
+		if err := file.WriteBundle(srv, bu); err != nil {
+			return nil, err
+		}
+		if err := forgetLocked(srv, environment, removed); err != nil {
+			return nil, err
+		}
+	}
+	if len(failed) > 0 {
+		return bu, errors.New(strings.Join(failed, "\n"))
+	}
+	return bu, nil
+}
+
+// printEnvPlugins prints the plugins that the environment being worked with
+// installs after a change when the output is JSON
+func printEnvPlugins(bu *file.BundleFile) error {
+	view, err := bu.ForEnv(environment)
+	if err != nil {
+		return err
+	}
+	return printBundlePlugins(view.Plugins)
+}
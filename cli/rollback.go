@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal"
+	"github.com/bennycio/bundle/internal/gate"
+	. "github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// rollbackCmd represents the rollback command
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <plugin> [version]",
+	Short: "Restore a previously installed version of a plugin",
+	Long: `Restore a jar that was replaced by an update. Every update keeps the replaced jar in
+	.bundle/history, if no version is specified the most recent kept version is restored. bundle.yml
+	and bundle.lock are updated to pin the restored version, in the environment given by --env`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ver := ""
+		if len(args) > 1 {
+			ver = args[1]
+		}
+		return rollback(file.NewLocalServer(""), args[0], ver)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+	rollbackCmd.Flags().StringVarP(&environment, "env", "e", "", "environment of bundle.yml to pin the restored version in, such as staging")
+}
+
+// rollback restores a kept jar of a plugin and pins bundle.yml and bundle.lock
+// to the restored version, in the environment when one is named
+func rollback(srv file.Server, pluginName, ver string) error {
+	gs := gate.NewGateService("localhost", "8020")
+
+	name := pluginName
+	if dbpl, err := gs.GetPlugin(&api.Plugin{Name: pluginName}); err == nil {
+		name = dbpl.Name
+	}
+
+	kept, err := file.HistoryVersions(srv, name)
+	if err != nil {
+		return err
+	}
+
+	current := ""
+	if plyml, err := file.GetPluginYml(srv, name); err == nil {
+		current = plyml.Version
+	}
+
+	if ver == "" {
+		for _, v := range kept {
+			if v != current {
+				ver = v
+				break
+			}
+		}
+		if ver == "" {
+			return fmt.Errorf("there are no previous versions of %s to roll back to", name)
+		}
+	} else if !internal.Contains(kept, ver) {
+		return fmt.Errorf("version %s of %s was not kept, kept versions are: %v", ver, name, kept)
+	}
+
+	base, err := file.GetBundle(srv)
+	if err != nil {
+		return err
+	}
+	bundle, err := base.ForEnv(environment)
+	if err != nil {
+		return err
+	}
+	key, ok := findPluginKey(bundle.Plugins, name)
+	if !ok {
+		key = name
+	}
+
+	if err := file.RollbackJar(srv, name, current, ver, viper.GetInt("historylimit")); err != nil {
+		return err
+	}
+
+	if err := base.SetPlugin(environment, key, ver); err != nil {
+		return err
+	}
+	if err := file.WriteBundle(srv, base); err != nil {
+		return err
+	}
+
+	if err := lockRollback(srv, key, name, ver); err != nil {
+		return err
+	}
+
+	if isJSONOutput() {
+		return printJSON(map[string]string{"plugin": name, "version": ver})
+	}
+	term.Println(Green(fmt.Sprintf("Rolled back %s to %s", name, ver)).Bold())
+	return nil
+}
+
+// lockRollback records the restored jar in bundle.lock, in the lock of the
+// environment when one is named
+func lockRollback(srv file.Server, key, name, ver string) error {
+	locked, err := lockedFromInstalled(srv, &api.Plugin{Name: name}, ver)
+	if err != nil {
+		return err
+	}
+
+	lock := &file.BundleLock{}
+	if file.IsLockInitialized(srv) {
+		lock, err = file.GetLock(srv)
+		if err != nil {
+			return err
+		}
+	}
+	lock.ForEnv(environment).Set(key, locked)
+	return file.WriteLock(srv, lock)
+}
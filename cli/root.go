@@ -1,10 +1,15 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/bennycio/bundle/cli/logger"
+	"github.com/bennycio/bundle/cli/term"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -18,11 +23,21 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if errors.Is(err, errUpdatesPending) {
+		os.Exit(exitUpdatesPending)
+	}
+	cobra.CheckErr(err)
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.SilenceErrors = true
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "output format, either text or json")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "answer every prompt with its default")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "never prompt, answering every prompt with its default")
 }
 
 func initConfig() {
@@ -38,6 +53,8 @@ func initConfig() {
 	viper.AddConfigPath(fmt.Sprintf("%s/.bundle", confDir))
 	viper.SetDefault("ftp", map[string]map[string]string{})
 	viper.SetDefault("debug", false)
+	viper.SetDefault("historylimit", 3)
+	viper.SetDefault("concurrency", 4)
 	viper.SetDefault("credentials", map[string]string{})
 	if err := viper.SafeWriteConfig(); err != nil {
 		if os.IsNotExist(err) {
@@ -58,4 +75,12 @@ func initConfig() {
 
 	configPath = fmt.Sprintf("%s/.bundle/config.yml", confDir)
 
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		term.Output = os.Stderr
+	case "text", "":
+	default:
+		logger.ErrLog.Fatalf("unknown output format %s, use text or json", outputFormat)
+	}
+
 }
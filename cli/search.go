@@ -37,6 +37,10 @@ var searchCmd = &cobra.Command{
 			return err
 		}
 
+		if isJSONOutput() {
+			return printJSON(results)
+		}
+
 		for i, v := range results {
 			fmt.Printf("%v. %s: \nDescription: %s\nAuthor: %s\n\n", i, v.Name, v.Description, v.Author.Username)
 		}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/cache"
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/bennycio/bundle/internal/gate"
+	"github.com/bennycio/bundle/internal/version"
+	"github.com/schollz/progressbar/v3"
+)
+
+// resolveServer looks up the server software of a bundle and decides which
+// version of it to install
+func resolveServer(sw *file.ServerSoftware) (*api.Plugin, error) {
+	if offline {
+		name, versions, err := cache.Versions(sw.Name)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := version.Latest(versions, sw.Version)
+		if err != nil {
+			return nil, fmt.Errorf("no cached version of %s matches %s", name, sw.Version)
+		}
+		return &api.Plugin{Name: name, Version: resolved, Type: api.ArtifactType_SERVER_SOFTWARE}, nil
+	}
+
+	gs := gate.NewGateService("localhost", "8020")
+	dbpl, err := gs.GetPlugin(&api.Plugin{Name: sw.Name})
+	if err != nil {
+		return nil, err
+	}
+	if dbpl.Type != api.ArtifactType_SERVER_SOFTWARE {
+		return nil, fmt.Errorf("%s is a plugin and not server software", dbpl.Name)
+	}
+	resolved, err := resolveVersion(dbpl, sw.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Plugin{Id: dbpl.Id, Name: dbpl.Name, Version: resolved, Type: dbpl.Type}, nil
+}
+
+// installServer installs the server jar of a bundle unless the jar that the
+// lock records is already installed. A frozen install installs exactly the
+// locked jar
+func installServer(ctx context.Context, srv file.Server, sw *file.ServerSoftware, token string, locked *file.LockedServer, frozen bool) (installReport, *file.LockedServer) {
+	report := installReport{Plugin: sw.Name}
+
+	var pl *api.Plugin
+	if frozen {
+		if locked == nil {
+			report.Outcome, report.Reason = outcomeFailed, fmt.Sprintf("the server is not in %s", file.LockFileName)
+			return report, nil
+		}
+		pl = &api.Plugin{Name: locked.Name, Version: locked.Version, Type: api.ArtifactType_SERVER_SOFTWARE}
+	} else {
+		resolved, err := resolveServer(sw)
+		if err != nil {
+			report.Outcome, report.Reason = outcomeFailed, err.Error()
+			return report, nil
+		}
+		pl = resolved
+	}
+	report.Plugin, report.Version = pl.Name, pl.Version
+
+	if locked != nil && locked.Version == pl.Version {
+		if sum, err := file.HashFile(srv, sw.JarName()); err == nil && sum == locked.Sha256 {
+			report.Outcome, report.Reason = outcomeSkipped, "up to date"
+			return report, locked
+		}
+	}
+
+	expected := ""
+	if frozen {
+		expected = locked.Sha256
+	}
+	fp, sum, err := fetchJar(ctx, pl, token, expected)
+	if err != nil {
+		report.Outcome, report.Reason = outcomeFailed, err.Error()
+		return report, nil
+	}
+	if err := ctx.Err(); err != nil {
+		report.Outcome, report.Reason = outcomeFailed, err.Error()
+		return report, nil
+	}
+
+	jar, err := os.Open(fp)
+	if err != nil {
+		report.Outcome, report.Reason = outcomeFailed, err.Error()
+		return report, nil
+	}
+	defer jar.Close()
+
+	size := int64(-1)
+	if fi, err := jar.Stat(); err == nil {
+		size = fi.Size()
+	}
+	pb := progressbar.DefaultBytes(size, fmt.Sprintf("Installing %s - %s", pl.Name, pl.Version))
+	if err := srv.WriteFile(sw.JarName(), io.TeeReader(jar, pb)); err != nil {
+		report.Outcome, report.Reason = outcomeFailed, err.Error()
+		return report, nil
+	}
+
+	gs := gate.NewGateService("localhost", "8020")
+	u, _ := gs.PluginDownloadUrl(pl)
+	report.Outcome = outcomeInstalled
+	return report, &file.LockedServer{
+		Name: pl.Name,
+		LockedPlugin: file.LockedPlugin{
+			Version: pl.Version,
+			URL:     u,
+			Sha256:  sum,
+		},
+	}
+}
+
+// checkServerDrift makes sure that bundle.lock still describes the server of
+// bundle.yml
+func checkServerDrift(sw *file.ServerSoftware, lock *file.BundleLock) []string {
+	if sw == nil {
+		if lock.Server != nil {
+			return []string{fmt.Sprintf("the server %s is locked but not in %s", lock.Server.Name, file.BuFileName)}
+		}
+		return nil
+	}
+	if lock.Server == nil {
+		return []string{fmt.Sprintf("the server %s is not in %s", sw.Name, file.LockFileName)}
+	}
+	if !version.Satisfies(lock.Server.Version, sw.Version) && sw.Version != lock.Server.Version {
+		return []string{fmt.Sprintf("the server %s wants version %s but %s has %s", sw.Name, sw.Version, file.LockFileName, lock.Server.Version)}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 
 	"github.com/alexeyco/simpletable"
@@ -10,31 +11,62 @@ import (
 	"github.com/bennycio/bundle/cli/file"
 	"github.com/bennycio/bundle/cli/term"
 	"github.com/bennycio/bundle/internal/gate"
-	"github.com/jlaffaye/ftp"
+	. "github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 )
 
+type pluginStatus struct {
+	Plugin          string `json:"plugin"`
+	Constraint      string `json:"constraint"`
+	Current         string `json:"current,omitempty"`
+	Latest          string `json:"latest"`
+	Installed       bool   `json:"installed"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+	// Warnings tell that the current version was yanked or that the plugin is
+	// deprecated
+	Warnings []string `json:"warnings,omitempty"`
+	// Error is why the newest version of the plugin could not be looked up
+	Error string `json:"error,omitempty"`
+}
+
+type statusResult struct {
+	Plugins   []pluginStatus `json:"plugins"`
+	Conflicts []string       `json:"conflicts"`
+}
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Check which plugins have updates.",
+	Long: `Check which plugins have updates. Exits with code 2 when any plugin is not installed or is
+	not at the newest version that bundle.yml allows, and with code 1 when any plugin could not be
+	looked up`,
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		bundle, err := file.GetBundle("")
+		srv := file.NewLocalServer("")
+
+		base, err := file.GetBundle(srv)
+		if err != nil {
+			return err
+		}
+		bundle, err := base.ForEnv(environment)
 		if err != nil {
 			return err
 		}
 
-		printStatus(bundle.Plugins, nil)
-		return nil
+		return printStatus(srv, bundle)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().StringVarP(&environment, "env", "e", "", "environment of bundle.yml to check, such as staging")
 }
 
-func printStatus(pls map[string]string, conn *ftp.ServerConn) {
-
-	pluginsToUpdate := sync.Map{}
+// getStatus compares every installed plugin with the newest version that
+// bundle.yml allows
+func getStatus(srv file.Server, pls map[string]string) []pluginStatus {
+	mu := &sync.Mutex{}
+	result := []pluginStatus{}
 
 	var wg sync.WaitGroup
 
@@ -49,41 +81,125 @@ func printStatus(pls map[string]string, conn *ftp.ServerConn) {
 			}
 			gs := gate.NewGateService("localhost", "8020")
 
+			failed := func(err error) {
+				st := pluginStatus{
+					Plugin:     pluginName,
+					Constraint: bundleVersion,
+					Error:      err.Error(),
+				}
+				if res, err := file.GetPluginYml(srv, pluginName); err == nil {
+					st.Current = res.Version
+					st.Installed = true
+				}
+				mu.Lock()
+				result = append(result, st)
+				mu.Unlock()
+			}
+
 			plugin, err := gs.GetPlugin(req)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "error occurred: %s\n", err.Error())
+				failed(err)
 				return
 			}
 
-			latestVersion := plugin.Version
-
-			if conn == nil {
-				if res, err := file.GetPluginYml(pluginName, nil); err == nil {
-					pls[pluginName] = res.Version
-					if res.Version != latestVersion {
-						pluginsToUpdate.Store(pluginName, latestVersion)
-					}
-				} else {
-					pls[pluginName] = "Not Installed"
-					pluginsToUpdate.Store(pluginName, latestVersion)
-				}
+			latestVersion, err := resolveVersion(plugin, bundleVersion)
+			if err != nil {
+				failed(err)
+				return
+			}
 
+			st := pluginStatus{
+				Plugin:     pluginName,
+				Constraint: bundleVersion,
+				Latest:     latestVersion,
+			}
+			if res, err := file.GetPluginYml(srv, pluginName); err == nil {
+				st.Current = res.Version
+				st.Installed = true
+				st.UpdateAvailable = !isUpToDate(res.Version, latestVersion, bundleVersion)
 			} else {
-				if res, err := file.GetPluginYml(pluginName, conn); err == nil {
-					pls[pluginName] = res.Version
-					if res.Version != latestVersion {
-						pluginsToUpdate.Store(pluginName, latestVersion)
-					}
-				} else {
-					pls[pluginName] = "Not Installed"
-					pluginsToUpdate.Store(pluginName, latestVersion)
-				}
+				st.UpdateAvailable = true
+			}
+			if warnings := releaseWarnings(plugin, st.Current); len(warnings) > 0 {
+				st.Warnings = warnings
 			}
 
+			mu.Lock()
+			result = append(result, st)
+			mu.Unlock()
 		}(k, v)
 	}
 	wg.Wait()
 
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Plugin < result[j].Plugin
+	})
+	return result
+}
+
+// serverStatus compares the installed server jar with the newest version that
+// bundle.yml allows. The installed version is only known when the jar is the
+// one that bundle.lock records
+func serverStatus(srv file.Server, sw *file.ServerSoftware) (pluginStatus, error) {
+	latest, err := resolveServer(sw)
+	if err != nil {
+		return pluginStatus{}, err
+	}
+	st := pluginStatus{
+		Plugin:          latest.Name,
+		Constraint:      sw.Version,
+		Latest:          latest.Version,
+		Installed:       srv.Exists(sw.JarName()),
+		UpdateAvailable: true,
+	}
+	if fullLock, err := file.GetLock(srv); err == nil {
+		locked := fullLock.ForEnv(environment).Server
+		if sum, err := file.HashFile(srv, sw.JarName()); err == nil && locked != nil && sum == locked.Sha256 {
+			st.Current = locked.Version
+			st.UpdateAvailable = !isUpToDate(st.Current, st.Latest, sw.Version)
+		}
+	}
+	return st, nil
+}
+
+// printStatus prints the server and the plugins that have updates or could not
+// be looked up. It returns errStatusFailed when any could not be looked up and
+// errUpdatesPending when any have updates
+func printStatus(srv file.Server, bundle *file.BundleFile) error {
+	statuses := getStatus(srv, bundle.Plugins)
+	if bundle.Server != nil {
+		st, err := serverStatus(srv, bundle.Server)
+		if err != nil {
+			st = pluginStatus{
+				Plugin:     bundle.Server.Name,
+				Constraint: bundle.Server.Version,
+				Installed:  srv.Exists(bundle.Server.JarName()),
+				Error:      err.Error(),
+			}
+		}
+		statuses = append([]pluginStatus{st}, statuses...)
+	}
+	conflicts := conflictingPairs(installedConflicts(srv))
+
+	var result error
+	for _, v := range statuses {
+		if v.UpdateAvailable && result == nil {
+			result = errUpdatesPending
+		}
+	}
+	for _, v := range statuses {
+		if v.Error != "" {
+			result = errStatusFailed
+		}
+	}
+
+	if isJSONOutput() {
+		if err := printJSON(statusResult{Plugins: statuses, Conflicts: conflicts}); err != nil {
+			fmt.Fprintf(os.Stderr, "error occurred: %s\n", err.Error())
+		}
+		return result
+	}
+
 	table := simpletable.New()
 
 	table.Header = &simpletable.Header{
@@ -94,22 +210,48 @@ func printStatus(pls map[string]string, conn *ftp.ServerConn) {
 		},
 	}
 
-	pluginsToUpdate.Range(func(key, value interface{}) bool {
+	for _, v := range statuses {
+		if !v.UpdateAvailable && v.Error == "" {
+			continue
+		}
+		current := v.Current
+		if !v.Installed {
+			current = "Not Installed"
+		} else if current == "" {
+			current = "Unknown"
+		}
+		latest := v.Latest
+		if v.Error != "" {
+			latest = "Lookup Failed"
+		}
 		r := []*simpletable.Cell{
-			{Text: key.(string)},
-			{Text: pls[key.(string)]},
-			{Text: value.(string)},
+			{Text: v.Plugin},
+			{Text: current},
+			{Text: latest},
 		}
 
 		table.Body.Cells = append(table.Body.Cells, r)
-		return true
-	})
+	}
 
 	table.SetStyle(simpletable.StyleCompactLite)
 	fmt.Println(table.String())
-	if conn == nil {
+	for _, v := range statuses {
+		if v.Error != "" {
+			term.Println(Red(fmt.Sprintf("Error: %s could not be looked up: %s", v.Plugin, v.Error)))
+		}
+	}
+	for _, v := range statuses {
+		for _, w := range v.Warnings {
+			term.Println(Yellow(fmt.Sprintf("Warning: %s", w)))
+		}
+	}
+	for _, v := range conflicts {
+		term.Println(Yellow(fmt.Sprintf("Warning: %s", v)))
+	}
+	if file.IsLocal(srv) {
 		term.Println(`Use "bundle install" to update your plugins`)
 	} else {
 		term.Println(`Use "install" to update your plugins`)
 	}
+	return result
 }
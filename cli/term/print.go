@@ -2,13 +2,19 @@ package term
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	. "github.com/logrusorgru/aurora"
 )
 
+// Output is where messages are printed, it is switched to stderr when the
+// output of a command has to stay machine readable
+var Output io.Writer = os.Stdout
+
 func Print(s interface{}) {
-	fmt.Printf("%s %s", Blue("[Bundle]"), s)
+	fmt.Fprintf(Output, "%s %s", Blue("[Bundle]"), s)
 }
 func Println(s interface{}) {
-	fmt.Printf("%s %s\n", Blue("[Bundle]"), s)
+	fmt.Fprintf(Output, "%s %s\n", Blue("[Bundle]"), s)
 }
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/bennycio/bundle/cli/file"
+	"github.com/spf13/cobra"
+)
+
+// uninstallCmd represents the uninstall command
+var uninstallCmd = &cobra.Command{
+	Use:     "uninstall",
+	Aliases: []string{"delete"},
+	Short:   "Delete plugins and remove them from your bundle.yml",
+	Long: `Delete the jars of plugins and remove them from your bundle.yml. With --env the plugins
+	are removed from that environment`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("please specify a plugin to remove")
+		}
+
+		srv := file.NewLocalServer("")
+
+		bu, err := removePlugins(srv, args, true)
+		if bu != nil {
+			if perr := printEnvPlugins(bu); perr != nil {
+				return perr
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+	uninstallCmd.Flags().StringVarP(&environment, "env", "e", "", "environment of bundle.yml to remove the plugins from, such as staging")
+}
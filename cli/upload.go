@@ -15,6 +15,7 @@ import (
 	"github.com/bennycio/bundle/cli/uploader"
 	"github.com/bennycio/bundle/internal"
 	"github.com/bennycio/bundle/internal/gate"
+	"github.com/bennycio/bundle/internal/version"
 	"github.com/c-bata/go-prompt"
 	"github.com/c-bata/go-prompt/completer"
 	. "github.com/logrusorgru/aurora"
@@ -37,9 +38,10 @@ var rdmeFileCompleter = completer.FilePathCompleter{
 // uploadCmd represents the upload command
 var uploadCmd = &cobra.Command{
 	Use:   "upload",
-	Short: "Upload your plugin as specified in bundle-make.yml to the official Bundle Repository",
-	Long: `Will upload the jar specified under JarPath into the official Bundle Repository, allowing public access
-	to your plugin. Version must be unique per upload and name must be unique globally for the initial upload`,
+	Short: "Upload a plugin jar to the official Bundle Repository",
+	Long: `Will upload the given jar into the official Bundle Repository, allowing public access
+	to your plugin. Version must be unique per upload and name must be unique globally for the initial upload.
+	Use bundle publish to upload as specified in bundle-make.yml without prompts`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		if !internal.IsValidPath(args[0]) {
@@ -48,7 +50,7 @@ var uploadCmd = &cobra.Command{
 
 		path := args[0]
 
-		user, err := getCurrentUser()
+		token, err := requireToken()
 		if err != nil {
 			return err
 		}
@@ -66,19 +68,39 @@ var uploadCmd = &cobra.Command{
 			return err
 		}
 
-		result, err := file.ParsePluginYml(plfile, info.Size())
+		if serverSoftware {
+			// server jars have no plugin.yml to read the name and version from
+			if uploadName == "" || uploadVersion == "" {
+				return errors.New("specify the --name and --version of the server software")
+			}
+			plugin.Name = uploadName
+			plugin.Version = uploadVersion
+			plugin.Type = api.ArtifactType_SERVER_SOFTWARE
+			plugin.Metadata = &api.PluginMetadata{}
+		} else {
+			result, err := file.ParsePluginYml(plfile, info.Size())
+
+			if err != nil {
+				return err
+			}
 
-		if err != nil {
-			return err
+			plugin.Name = result.Name
+			plugin.Version = result.Version
+			plugin.Description = result.Description
+			plugin.Category = api.Category(result.Category)
+			plugin.Metadata = &api.PluginMetadata{
+				Conflicts:  result.Conflicts,
+				Depend:     result.Depend,
+				Softdepend: result.SoftDepend,
+				Loadbefore: result.LoadBefore,
+			}
 		}
 
-		plugin.Name = result.Name
-		plugin.Version = result.Version
-		plugin.Description = result.Description
-		plugin.Category = api.Category(result.Category)
-		plugin.Metadata = &api.PluginMetadata{
-			Conflicts: result.Conflicts,
+		channel, ok := version.ParseChannel(uploadChannel)
+		if !ok {
+			return fmt.Errorf("--channel must be one of %s", strings.Join(version.Channels, ", "))
 		}
+		plugin.Channel = channel
 
 		gs := gate.NewGateService("localhost", "8020")
 
@@ -94,18 +116,27 @@ var uploadCmd = &cobra.Command{
 		upl := &uploader.Uploader{
 			PluginFile: fi,
 			Plugin:     plugin,
-			User:       user,
+			Token:      token,
 		}
 
 		term.Println(Green("Queued Plugin for Upload! :)! :)").Bold())
 
-		term.Println("Would you like to upload a README as well? [Y/n]")
+		rdmeToo := confirm("Would you like to upload a README as well?", true)
 
-		rdmeToo := prompt.Input(">> ", yesOrNoCompleter)
+		// without prompts the walker cannot ask which README to use, so only
+		// the one in the working directory is picked up
+		if rdmeToo && !isInteractive() {
+			if _, err := os.Stat("README.md"); err != nil {
+				rdmeToo = false
+			}
+		}
 
-		if strings.EqualFold(rdmeToo, "y") || strings.EqualFold(rdmeToo, "yes") || rdmeToo == "" {
-			term.Println("Please specify a path to your readme file or press enter to scan for readme in close directories.")
-			p := prompt.Input(">> ", rdmeFileCompleter.Complete, prompt.OptionCompletionWordSeparator(completer.FilePathCompletionSeparator))
+		if rdmeToo {
+			p := "README.md"
+			if isInteractive() {
+				term.Println("Please specify a path to your readme file or press enter to scan for readme in close directories.")
+				p = prompt.Input(">> ", rdmeFileCompleter.Complete, prompt.OptionCompletionWordSeparator(completer.FilePathCompletionSeparator))
+			}
 
 			if p == "" {
 				wlk := uploader.NewFileWalker("README.md", plugin.Name)
@@ -149,9 +180,10 @@ var uploadCmd = &cobra.Command{
 		}
 
 		if isUpdating {
-			if ch, err := makeChangelog(dbPl.Id, plugin.Version); err != nil {
+			if ch, err := makeChangelog(dbPl.Id, dbPl.Version, plugin.Version); err != nil {
 				return err
 			} else {
+				ch.Channel = plugin.Channel
 				upl.Changelog = ch
 				term.Println(Green("Queued Changelog for Upload! :)").Bold())
 			}
@@ -161,79 +193,31 @@ var uploadCmd = &cobra.Command{
 			return err
 		}
 
+		if isJSONOutput() {
+			return printJSON(map[string]string{"name": plugin.Name, "version": plugin.Version})
+		}
 		return nil
 	},
 }
 
+var serverSoftware bool
+
+var uploadName string
+
+var uploadVersion string
+
+var uploadChannel string
+
+var changelogPath string
+
+var changelogFromGit bool
+
 func init() {
 	rootCmd.AddCommand(uploadCmd)
-}
-
-func makeChangelog(pluginId, version string) (*api.Changelog, error) {
-	addedList := []string{}
-	updatedList := []string{}
-	removedList := []string{}
-
-	term.Println("What did you add in this version?")
-	term.Println(Gray(12, "Press enter on an empty line to continue"))
-	for {
-		added := prompt.Input(">> ", nilCompleter)
-		if strings.Trim(strings.TrimSpace(added), "\n") == "" {
-			break
-		}
-		addedList = append(addedList, added)
-	}
-	term.Println("What did you remove in this version?")
-	term.Println(Gray(12, "Press enter on an empty line to continue"))
-	for {
-		removed := prompt.Input(">> ", nilCompleter)
-		if strings.Trim(strings.TrimSpace(removed), "\n") == "" {
-			break
-		}
-		removedList = append(removedList, removed)
-	}
-	term.Println("What did you update in this version?")
-	term.Println(Gray(12, "Press enter on an empty line to continue"))
-	for {
-		updated := prompt.Input(">> ", nilCompleter)
-		if strings.Trim(strings.TrimSpace(updated), "\n") == "" {
-			break
-		}
-		updatedList = append(updatedList, updated)
-	}
-
-	changelog := &api.Changelog{
-		PluginId: pluginId,
-		Version:  version,
-		Added:    addedList,
-		Removed:  removedList,
-		Updated:  updatedList,
-	}
-
-	term.Println("Is this correct? [Y/n]")
-	fmt.Println(Green("Added: ").Bold())
-	for _, v := range addedList {
-		fmt.Printf("  - %s\n", Green(v))
-	}
-	fmt.Println(Red("Removed: ").Bold())
-	for _, v := range removedList {
-		fmt.Printf("  - %s\n", Red(v))
-	}
-	fmt.Println(Blue("Updated: ").Bold())
-	for _, v := range updatedList {
-		fmt.Printf("  - %s\n", Blue(v))
-	}
-
-	correct := prompt.Input(">> ", yesOrNoCompleter)
-
-	if strings.EqualFold(correct, "y") || strings.EqualFold(correct, "yes") || correct == "" {
-		return changelog, nil
-	} else {
-		c, err := makeChangelog(pluginId, version)
-		if err != nil {
-			return nil, err
-		}
-		changelog = c
-	}
-	return changelog, nil
+	uploadCmd.Flags().BoolVar(&serverSoftware, "server", false, "upload server software, such as a Paper jar, instead of a plugin")
+	uploadCmd.Flags().StringVar(&uploadName, "name", "", "name of the server software, only used with --server")
+	uploadCmd.Flags().StringVar(&uploadVersion, "version", "", "version of the server software, only used with --server")
+	uploadCmd.Flags().StringVar(&uploadChannel, "channel", version.Stable, "release channel to upload to, one of stable, beta or alpha")
+	uploadCmd.Flags().StringVar(&changelogPath, "changelog", "", "read the changelog from the section of the version in this file, such as CHANGELOG.md")
+	uploadCmd.Flags().BoolVar(&changelogFromGit, "changelog-from-git", false, "build the changelog from the conventional commits since the tag of the previous version")
 }
@@ -10,41 +10,78 @@ import (
 
 type Uploader struct {
 	PluginFile *os.File
-	User       *api.User
-	Plugin     *api.Plugin
-	Readme     *api.Readme
-	Changelog  *api.Changelog
+	// Token is the API token that the upload is made with
+	Token     string
+	Plugin    *api.Plugin
+	Readme    *api.Readme
+	Changelog *api.Changelog
+	// Thumbnail is the image shown next to the plugin on the website
+	Thumbnail *os.File
+	// Silent hides the progress bars, for output that is read by scripts
+	Silent bool
 }
 
 func (u *Uploader) Upload() error {
-	gservice := gate.NewGateService("localhost", "8020")
+	gservice := gate.NewGateServiceWithToken("localhost", "8020", u.Token)
 	if u.Plugin != nil && u.PluginFile != nil {
 		fi, err := u.PluginFile.Stat()
 		if err != nil {
 			return err
 		}
-		pb := progressbar.DefaultBytes(fi.Size(), "Uploading Plugin...")
+		pb := u.bytesBar(fi.Size(), "Uploading Plugin...")
 
 		rdr := progressbar.NewReader(u.PluginFile, pb)
 
-		err = gservice.UploadPlugin(u.User, u.Plugin, &rdr)
+		err = gservice.UploadPlugin(nil, u.Plugin, &rdr)
 		if err != nil {
 			return err
 		}
 	}
+	if u.Plugin != nil && (u.Thumbnail != nil || (u.Changelog != nil && u.Changelog.PluginId == "")) {
+		// the id of a plugin is only known once its first version is uploaded
+		dbPlugin, err := gservice.GetPlugin(&api.Plugin{Name: u.Plugin.Name})
+		if err != nil {
+			return err
+		}
+		u.Plugin.Id = dbPlugin.Id
+		if u.Changelog != nil && u.Changelog.PluginId == "" {
+			u.Changelog.PluginId = dbPlugin.Id
+		}
+	}
 	if u.Readme != nil {
-		pb := progressbar.Default(1, "Uploading Readme...")
-		if err := gservice.InsertReadme(u.User, u.Readme); err != nil {
+		pb := u.bar("Uploading Readme...")
+		if err := gservice.InsertReadme(nil, u.Readme); err != nil {
 			return err
 		}
 		pb.Add(1)
 	}
 	if u.Changelog != nil {
-		pb := progressbar.Default(1, "Uploading Changelog...")
-		if err := gservice.InsertChangelog(u.User, u.Changelog); err != nil {
+		pb := u.bar("Uploading Changelog...")
+		if err := gservice.InsertChangelog(nil, u.Changelog); err != nil {
+			return err
+		}
+		pb.Add(1)
+	}
+	if u.Thumbnail != nil {
+		pb := u.bar("Uploading Thumbnail...")
+		if err := gservice.UploadThumbnail(nil, u.Plugin, u.Thumbnail); err != nil {
 			return err
 		}
 		pb.Add(1)
 	}
 	return nil
 }
+
+func (u *Uploader) bytesBar(size int64, description string) *progressbar.ProgressBar {
+	if u.Silent {
+		return progressbar.DefaultBytesSilent(size, description)
+	}
+	return progressbar.DefaultBytes(size, description)
+}
+
+func (u *Uploader) bar(description string) *progressbar.ProgressBar {
+	if u.Silent {
+		return progressbar.DefaultSilent(1, description)
+	}
+	return progressbar.Default(1, description)
+}
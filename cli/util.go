@@ -1,20 +1,23 @@
 package cli
 
 import (
-	"encoding/base64"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"syscall"
-	"unicode"
 
 	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/credentials"
 	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal/gate"
+	"github.com/bennycio/bundle/internal/version"
 	"github.com/c-bata/go-prompt"
+	. "github.com/logrusorgru/aurora"
 	"github.com/spf13/viper"
 	goterm "golang.org/x/term"
+	"gopkg.in/yaml.v2"
 )
 
 func isPluginDirectory(path string) bool {
@@ -45,38 +48,152 @@ func credentialsPrompt() *api.User {
 	return user
 }
 
+// findPluginKey returns the key a plugin is listed under, ignoring case
+func findPluginKey(plugins map[string]string, pluginName string) (string, bool) {
+	for k := range plugins {
+		if strings.EqualFold(k, pluginName) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
 func nilCompleter(d prompt.Document) []prompt.Suggest {
 	return nil
 }
 
-func versionGreaterThan(version, than string) bool {
+// availableVersions lists every version of a plugin that the repository knows
+// about along with its release channel
+func availableVersions(plugin *api.Plugin) (map[string]string, error) {
+	result := map[string]string{}
+	if plugin.Version != "" {
+		result[plugin.Version] = plugin.Channel
+	}
+	for ch, v := range plugin.Channels {
+		result[v] = ch
+	}
+
+	gs := gate.NewGateService("localhost", "8020")
+
+	resp, err := gs.GetChangelogs(&api.Changelog{PluginId: plugin.Id})
+	if err != nil {
+		return result, err
+	}
+
+	for _, v := range resp.Changelogs {
+		if _, ok := result[v.Version]; !ok {
+			result[v.Version] = v.Channel
+		}
+	}
 
-	isNumber := func(r rune) rune {
-		if unicode.IsDigit(r) {
-			return r
+	releases, err := gs.GetReleases(&api.Plugin{Id: plugin.Id})
+	if err != nil {
+		return result, err
+	}
+	for _, v := range releases.Releases {
+		if _, ok := result[v.Version]; !ok {
+			result[v.Version] = v.Channel
 		}
-		return 0
+	}
+	return result, nil
+}
+
+// withChannel opts a constraint in to the release channel of --channel unless
+// it names a channel itself
+func withChannel(constraint string) string {
+	if releaseChannel == "" {
+		return constraint
+	}
+	if _, ch := version.SplitChannel(constraint); ch != "" {
+		return constraint
+	}
+	if strings.TrimSpace(constraint) == "" || strings.EqualFold(strings.TrimSpace(constraint), "latest") {
+		return releaseChannel
+	}
+	return constraint + "@" + releaseChannel
+}
+
+// resolveVersion picks the newest version of a plugin that satisfies the
+// constraint from bundle.yml out of the releases of the channels that it opts
+// in to, stable releases only unless it names a channel such as "^2.1@beta".
+// Yanked versions are never picked. Exact versions are passed through
+// untouched so that releases without a changelog and yanked releases can
+// still be pinned
+func resolveVersion(plugin *api.Plugin, constraint string) (string, error) {
+	constraint = withChannel(constraint)
+	rng, channel := version.SplitChannel(constraint)
+	if _, err := version.ParseConstraint(constraint); err != nil {
+		return strings.TrimSpace(rng), nil
 	}
 
-	versionNoChars := strings.Map(isNumber, version)
-	fmt.Println(versionNoChars)
+	exact := strings.TrimPrefix(strings.TrimSpace(rng), "=")
+	_, perr := version.Parse(exact)
+	pinned := perr == nil
 
-	thanNoChars := strings.Map(isNumber, than)
-	fmt.Println(thanNoChars)
+	channels, _ := availableVersions(plugin)
 
-	for i, v := range thanNoChars {
-		if len(versionNoChars) < i+1 {
-			break
+	versions := []string{}
+	yanked := 0
+	for v, ch := range channels {
+		if !version.ChannelIncludes(channel, ch) {
+			continue
 		}
-		fmt.Printf("Ver - %v Than - %v", rune(versionNoChars[i]), v)
-		if rune(versionNoChars[i]) == v {
+		if _, ok := plugin.Yanked[v]; ok {
+			yanked++
 			continue
 		}
-		return rune(versionNoChars[i]) > v
+		versions = append(versions, v)
+	}
+	if len(versions) == 0 {
+		if pinned {
+			return exact, nil
+		}
+		if channel == "" {
+			channel = version.Stable
+		}
+		if yanked > 0 {
+			return "", fmt.Errorf("every %s release of %s was yanked", channel, plugin.Name)
+		}
+		return "", fmt.Errorf("%s has no %s releases, opt in to another channel such as %s@%s", plugin.Name, channel, plugin.Name, version.Beta)
 	}
 
-	return false
+	result, err := version.Latest(versions, constraint)
+	if err != nil {
+		if pinned {
+			return exact, nil
+		}
+		return "", fmt.Errorf("%s: %s", plugin.Name, err.Error())
+	}
+	return result, nil
+}
 
+// releaseWarnings explains why a version of a plugin should not be used, when
+// it was yanked or the plugin is deprecated
+func releaseWarnings(plugin *api.Plugin, ver string) []string {
+	result := []string{}
+	if plugin == nil {
+		return result
+	}
+	if reason, ok := plugin.Yanked[ver]; ok && ver != "" {
+		result = append(result, fmt.Sprintf("%s %s was yanked: %s", plugin.Name, ver, reason))
+	}
+	if plugin.Deprecation != nil {
+		msg := fmt.Sprintf("%s is deprecated: %s", plugin.Name, plugin.Deprecation.Reason)
+		if plugin.Deprecation.Replacement != "" {
+			msg += fmt.Sprintf(", use %s instead", plugin.Deprecation.Replacement)
+		}
+		result = append(result, msg)
+	}
+	return result
+}
+
+// isUpToDate reports whether an installed version can be kept instead of
+// installing the resolved version
+func isUpToDate(installed, resolved, constraint string) bool {
+	if installed == resolved {
+		return true
+	}
+	return version.Satisfies(installed, constraint) && version.Compare(installed, resolved) >= 0
 }
 
 func completerWithOptions(ss ...string) func(prompt.Document) []prompt.Suggest {
@@ -100,47 +217,76 @@ func yesOrNoCompleter(d prompt.Document) []prompt.Suggest {
 	return prompt.FilterHasPrefix(s, d.GetWordBeforeCursor(), true)
 }
 
-func getCurrentUser() (*api.User, error) {
-	if viper.IsSet("credentials") {
-		creds := viper.GetStringMap("credentials")
-		user := &api.User{}
-
-		if un, ok := creds["username"].(string); ok {
-			user.Username = un
-		} else {
-			return newUser()
-		}
-
-		if pass, ok := creds["password"].(string); ok {
-			res, err := base64.StdEncoding.DecodeString(pass)
-			if err != nil {
-				return nil, err
-			}
-			user.Password = string(res)
-		} else {
-			return newUser()
-		}
-		return user, nil
-	} else {
-		return newUser()
+// currentToken returns the saved API token, or nothing when the CLI is not
+// logged in. Only premium plugins need a token to be downloaded
+func currentToken() string {
+	token, _, err := credentials.Load()
+	if err != nil {
+		return ""
 	}
+	return token
 }
 
-func newUser() (*api.User, error) {
-	user := credentialsPrompt()
-
-	// encode base64 so sneaky shoulder peekers don't catch a quick password
+// requireToken returns the saved API token, logging in first when there is
+// none and prompts are allowed
+func requireToken(scopes ...string) (string, error) {
+	token, _, err := credentials.Load()
+	if err == nil {
+		return token, nil
+	}
+	if err != credentials.ErrNotLoggedIn || !isInteractive() {
+		return "", err
+	}
+	res, err := login(credentialsPrompt(), scopes)
+	if err != nil {
+		return "", err
+	}
+	return res.Token, nil
+}
 
-	pass := base64.StdEncoding.EncodeToString([]byte(user.Password))
-	creds := map[string]string{
-		"username": user.Username,
-		"password": pass,
+// login trades credentials for an API token and saves it. The password that
+// older versions saved in the config file is forgotten
+func login(user *api.User, scopes []string) (*gate.ApiToken, error) {
+	gs := gate.NewGateService("localhost", "8020")
+	res, err := gs.CreateToken(user, scopes)
+	if err != nil {
+		return nil, err
 	}
-	viper.Set("credentials", creds)
-	err := viper.WriteConfig()
+	store, err := credentials.Save(res.Token)
 	if err != nil {
 		return nil, err
 	}
-	return user, nil
+	if store == credentials.StoreFile {
+		fp, _ := credentials.TokenFile()
+		term.Println(Yellow(fmt.Sprintf("There is no keyring, so your token is saved in plain text in %s where only you can read it. Set %s and log in again to encrypt it", fp, credentials.EnvPassphrase)))
+	}
+	if err := forgetSavedPassword(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
 
+// forgetSavedPassword removes the credentials that older versions kept in the
+// config file
+func forgetSavedPassword() error {
+	if !viper.IsSet("credentials") || configPath == "" {
+		return nil
+	}
+	bs, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	conf := map[string]interface{}{}
+	if err := yaml.Unmarshal(bs, &conf); err != nil {
+		return err
+	}
+	delete(conf, "credentials")
+	bs, err = yaml.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(configPath, bs, 0600); err != nil {
+		return err
+	}
+	return viper.ReadInConfig()
 }
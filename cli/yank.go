@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/cli/term"
+	"github.com/bennycio/bundle/internal/gate"
+	. "github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+// yankCmd represents the yank command
+var yankCmd = &cobra.Command{
+	Use:   "yank <plugin>@<version>",
+	Short: "Yank a broken version of your plugin",
+	Long: `Hides a version of your plugin from "latest" and from version resolution. Servers that
+pin the exact version can still download it but are warned with the reason. Use --undo to
+make the version resolvable again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		split := strings.SplitN(args[0], "@", 2)
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return errors.New("please specify the version to yank as plugin@version")
+		}
+		if !yankUndo && yankReason == "" {
+			return errors.New("please give a reason with --reason so that users know why the version was yanked")
+		}
+
+		token, err := requireToken(gate.ScopePublish)
+		if err != nil {
+			return err
+		}
+		gs := gate.NewGateServiceWithToken("localhost", "8020", token)
+
+		plugin := &api.Plugin{Name: split[0], Version: split[1]}
+		if yankUndo {
+			err = gs.UnyankVersion(nil, plugin)
+		} else {
+			err = gs.YankVersion(nil, plugin, yankReason)
+		}
+		if err != nil {
+			return err
+		}
+
+		result := yanked{Plugin: plugin.Name, Version: plugin.Version, Yanked: !yankUndo, Reason: yankReason}
+		if isJSONOutput() {
+			return printJSON(result)
+		}
+		if yankUndo {
+			term.Println(Green(fmt.Sprintf("%s %s can be resolved again", plugin.Name, plugin.Version)).Bold())
+		} else {
+			term.Println(Yellow(fmt.Sprintf("Yanked %s %s: %s", plugin.Name, plugin.Version, yankReason)).Bold())
+		}
+		return nil
+	},
+}
+
+// deprecateCmd represents the deprecate command
+var deprecateCmd = &cobra.Command{
+	Use:   "deprecate <plugin>",
+	Short: "Deprecate your plugin",
+	Long: `Marks your plugin as deprecated so that "bundle status" and "bundle install" warn everyone
+who uses it, optionally pointing them to its replacement. Use --undo to remove the deprecation.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !deprecateUndo && deprecateReason == "" {
+			return errors.New("please give a reason with --reason so that users know why the plugin was deprecated")
+		}
+
+		token, err := requireToken(gate.ScopePublish)
+		if err != nil {
+			return err
+		}
+		gs := gate.NewGateServiceWithToken("localhost", "8020", token)
+
+		plugin := &api.Plugin{Name: args[0]}
+		dep := &api.Deprecation{Reason: deprecateReason, Replacement: deprecateReplacement}
+		if deprecateUndo {
+			err = gs.UndeprecatePlugin(nil, plugin)
+		} else {
+			err = gs.DeprecatePlugin(nil, plugin, dep)
+		}
+		if err != nil {
+			return err
+		}
+
+		result := deprecated{Plugin: plugin.Name, Deprecated: !deprecateUndo}
+		if !deprecateUndo {
+			result.Reason = dep.Reason
+			result.Replacement = dep.Replacement
+		}
+		if isJSONOutput() {
+			return printJSON(result)
+		}
+		if deprecateUndo {
+			term.Println(Green(plugin.Name + " is no longer deprecated").Bold())
+			return nil
+		}
+		msg := fmt.Sprintf("Deprecated %s: %s", plugin.Name, dep.Reason)
+		if dep.Replacement != "" {
+			msg += fmt.Sprintf(", use %s instead", dep.Replacement)
+		}
+		term.Println(Yellow(msg).Bold())
+		return nil
+	},
+}
+
+var (
+	yankReason string
+	yankUndo   bool
+)
+
+var (
+	deprecateReason      string
+	deprecateReplacement string
+	deprecateUndo        bool
+)
+
+func init() {
+	rootCmd.AddCommand(yankCmd)
+	yankCmd.Flags().StringVarP(&yankReason, "reason", "r", "", "why the version was yanked, shown to everyone who pins it")
+	yankCmd.Flags().BoolVar(&yankUndo, "undo", false, "make a yanked version resolvable again")
+
+	rootCmd.AddCommand(deprecateCmd)
+	deprecateCmd.Flags().StringVarP(&deprecateReason, "reason", "r", "", "why the plugin is deprecated")
+	deprecateCmd.Flags().StringVar(&deprecateReplacement, "replacement", "", "plugin that users should switch to")
+	deprecateCmd.Flags().BoolVar(&deprecateUndo, "undo", false, "remove the deprecation")
+}
+
+type yanked struct {
+	Plugin  string `json:"plugin"`
+	Version string `json:"version"`
+	Yanked  bool   `json:"yanked"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type deprecated struct {
+	Plugin      string `json:"plugin"`
+	Deprecated  bool   `json:"deprecated"`
+	Reason      string `json:"reason,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
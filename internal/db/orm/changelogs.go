@@ -15,6 +15,7 @@ type changelog struct {
 	Added    []string           `bson:"added,omitempty" json:"added"`
 	Removed  []string           `bson:"removed,omitempty" json:"removed"`
 	Updated  []string           `bson:"updated,omitempty" json:"updated"`
+	Channel  string             `bson:"channel,omitempty" json:"channel"`
 }
 type ChangelogOrm struct{}
 
@@ -158,6 +159,7 @@ func apiToOrmChangelog(ch *api.Changelog) changelog {
 		Added:   ch.Added,
 		Removed: ch.Removed,
 		Updated: ch.Updated,
+		Channel: ch.Channel,
 	}
 
 	if ch.Id != "" {
@@ -184,5 +186,6 @@ func ormToApiChangelog(ch changelog) *api.Changelog {
 		Added:    ch.Added,
 		Removed:  ch.Removed,
 		Updated:  ch.Updated,
+		Channel:  ch.Channel,
 	}
 }
@@ -23,6 +23,23 @@ type plugin struct {
 	Metadata    metadata           `bson:"metadata,omitempty" json:"metadata"`
 	Premium     premium            `bson:"premium,omitempty" json:"premium"`
 	LastUpdated primitive.DateTime `bson:"lastUpdated,omitempty" json:"lastUpdated"`
+	Type        artifactType       `bson:"type,omitempty" json:"type"`
+	Channel     string             `bson:"channel,omitempty" json:"channel"`
+	Channels    map[string]string  `bson:"channels,omitempty" json:"channels"`
+	Yanked      []yank             `bson:"yanked,omitempty" json:"yanked"`
+	Deprecation *deprecation       `bson:"deprecation,omitempty" json:"deprecation"`
+}
+
+// yank is a version that is hidden from version resolution. Versions are not
+// used as keys because they contain dots
+type yank struct {
+	Version string `bson:"version" json:"version"`
+	Reason  string `bson:"reason" json:"reason"`
+}
+
+type deprecation struct {
+	Reason      string `bson:"reason" json:"reason"`
+	Replacement string `bson:"replacement,omitempty" json:"replacement"`
 }
 
 type premium struct {
@@ -31,8 +48,11 @@ type premium struct {
 }
 
 type metadata struct {
-	Downloads int64    `bson:"downloads,omitempty" json:"price"`
-	Conflicts []string `bson:"conflicts,omitempty" json:"purchases"`
+	Downloads  int64    `bson:"downloads,omitempty" json:"price"`
+	Conflicts  []string `bson:"conflicts,omitempty" json:"purchases"`
+	Depend     []string `bson:"depend,omitempty" json:"depend"`
+	SoftDepend []string `bson:"softdepend,omitempty" json:"softdepend"`
+	LoadBefore []string `bson:"loadbefore,omitempty" json:"loadbefore"`
 }
 type category int32
 
@@ -50,6 +70,15 @@ const (
 	lib
 )
 
+// artifactType tells plugins apart from server software, plugins are stored
+// without a type
+type artifactType int32
+
+const (
+	pluginArtifact artifactType = iota
+	serverSoftwareArtifact
+)
+
 type PluginsOrm struct{}
 
 func NewPluginsOrm() *PluginsOrm { return &PluginsOrm{} }
@@ -133,6 +162,91 @@ func (p *PluginsOrm) Update(req *api.Plugin) error {
 
 }
 
+// UpdateYanked changes only the yanked versions of a plugin. A version with
+// a reason is yanked, a version with an empty reason is taken off the list
+func (p *PluginsOrm) UpdateYanked(req *api.Plugin) error {
+	mgses, err := getMongoSession()
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+	defer mgses.Cancel()
+
+	collection := mgses.Client.Database("plugins").Collection("plugins")
+
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil || id == primitive.NilObjectID {
+		err = errors.New("id required to update yanked versions")
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+
+	for version, reason := range req.Yanked {
+		// a yank is pulled before it is pushed again so that a version is
+		// never listed twice, the same field cannot be pulled and pushed in
+		// one update
+		res, err := collection.UpdateByID(mgses.Ctx, id, bson.D{{"$pull", bson.D{{"yanked", bson.D{{"version", version}}}}}})
+		if err != nil {
+			logger.ErrLog.Print(err.Error())
+			return err
+		}
+		if res.MatchedCount < 1 {
+			err = errors.New("no plugin found")
+			logger.ErrLog.Print(err.Error())
+			return err
+		}
+		if reason == "" {
+			continue
+		}
+		_, err = collection.UpdateByID(mgses.Ctx, id, bson.D{{"$push", bson.D{{"yanked", yank{Version: version, Reason: reason}}}}})
+		if err != nil {
+			logger.ErrLog.Print(err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateDeprecation changes only the deprecation of a plugin, a deprecation
+// with an empty reason removes it
+func (p *PluginsOrm) UpdateDeprecation(req *api.Plugin) error {
+	mgses, err := getMongoSession()
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+	defer mgses.Cancel()
+
+	collection := mgses.Client.Database("plugins").Collection("plugins")
+
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil || id == primitive.NilObjectID || req.Deprecation == nil {
+		err = errors.New("id and deprecation required to update the deprecation")
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+
+	change := bson.D{{"$unset", bson.D{{"deprecation", ""}}}}
+	if req.Deprecation.Reason != "" {
+		change = bson.D{{"$set", bson.D{{"deprecation", deprecation{
+			Reason:      req.Deprecation.Reason,
+			Replacement: req.Deprecation.Replacement,
+		}}}}}
+	}
+
+	res, err := collection.UpdateByID(mgses.Ctx, id, change)
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+	if res.MatchedCount < 1 {
+		err = errors.New("no plugin found")
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+	return nil
+}
+
 func (p *PluginsOrm) Get(req *api.Plugin) (*api.Plugin, error) {
 	session, err := getMongoSession()
 	if err != nil {
@@ -239,7 +353,7 @@ func validatePluginInsert(pl plugin) error {
 	if pl.Name == "" {
 		return errors.New("name required for insertion")
 	}
-	if pl.Version == "" {
+	if pl.Version == "" && len(pl.Channels) == 0 {
 		return errors.New("version required for insertion")
 	}
 	if pl.Author == primitive.NilObjectID {
@@ -264,14 +378,32 @@ func ormToApiPl(pl plugin) *api.Plugin {
 		Thumbnail:   pl.Thumbnail,
 		Category:    api.Category(pl.Category),
 		Metadata: &api.PluginMetadata{
-			Downloads: pl.Metadata.Downloads,
-			Conflicts: pl.Metadata.Conflicts,
+			Downloads:  pl.Metadata.Downloads,
+			Conflicts:  pl.Metadata.Conflicts,
+			Depend:     pl.Metadata.Depend,
+			Softdepend: pl.Metadata.SoftDepend,
+			Loadbefore: pl.Metadata.LoadBefore,
 		},
 		Premium: &api.Premium{
 			Price:     pl.Premium.Price,
 			Purchases: pl.Premium.Purchases,
 		},
 		LastUpdated: pl.LastUpdated.Time().Unix(),
+		Type:        api.ArtifactType(pl.Type),
+		Channel:     pl.Channel,
+		Channels:    pl.Channels,
+	}
+	if len(pl.Yanked) > 0 {
+		p.Yanked = map[string]string{}
+		for _, v := range pl.Yanked {
+			p.Yanked[v.Version] = v.Reason
+		}
+	}
+	if pl.Deprecation != nil {
+		p.Deprecation = &api.Deprecation{
+			Reason:      pl.Deprecation.Reason,
+			Replacement: pl.Deprecation.Replacement,
+		}
 	}
 	a, err := NewUsersOrm().Get(&api.User{Id: pl.Author.Hex()})
 	if err == nil {
@@ -299,6 +431,9 @@ func apiToOrmPl(pl *api.Plugin) plugin {
 		Thumbnail:   pl.Thumbnail,
 		LastUpdated: lastUpdated,
 		Category:    category(pl.Category),
+		Type:        artifactType(pl.Type),
+		Channel:     pl.Channel,
+		Channels:    pl.Channels,
 	}
 	pluginID, err := primitive.ObjectIDFromHex(pl.Id)
 	if pluginID != primitive.NilObjectID && err == nil {
@@ -318,8 +453,11 @@ func apiToOrmPl(pl *api.Plugin) plugin {
 	}
 	if pl.Metadata != nil {
 		result.Metadata = metadata{
-			Downloads: pl.Metadata.Downloads,
-			Conflicts: pl.Metadata.Conflicts,
+			Downloads:  pl.Metadata.Downloads,
+			Conflicts:  pl.Metadata.Conflicts,
+			Depend:     pl.Metadata.Depend,
+			SoftDepend: pl.Metadata.Softdepend,
+			LoadBefore: pl.Metadata.Loadbefore,
 		}
 	}
 
@@ -0,0 +1,228 @@
+package orm
+
+import (
+	"errors"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type release struct {
+	Id               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PluginId         primitive.ObjectID `bson:"pluginId,omitempty" json:"pluginId"`
+	Version          string             `bson:"version,omitempty" json:"version"`
+	Size             int64              `bson:"size,omitempty" json:"size"`
+	Sha256           string             `bson:"sha256,omitempty" json:"sha256"`
+	UploadedAt       int64              `bson:"uploadedAt,omitempty" json:"uploadedAt"`
+	Channel          string             `bson:"channel,omitempty" json:"channel"`
+	Uploader         primitive.ObjectID `bson:"uploader,omitempty" json:"uploader"`
+	MinecraftVersion string             `bson:"minecraftVersion,omitempty" json:"minecraftVersion"`
+	// Metadata is nil for releases recorded before their dependencies were
+	Metadata *metadata `bson:"metadata,omitempty" json:"metadata"`
+}
+
+// ErrReleaseExists is returned when a version of a plugin is recorded again,
+// releases are immutable
+var ErrReleaseExists = errors.New("release already exists, versions cannot be replaced")
+
+// InsertRelease records a version of a plugin. A version is only ever
+// recorded once, which makes the record the lock that concurrent uploads of
+// the same version race for
+func (p *PluginsOrm) InsertRelease(rel *api.Release) error {
+	mgses, err := getMongoSession()
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+	defer mgses.Cancel()
+
+	collection := mgses.Client.Database("plugins").Collection("releases")
+
+	s := apiToOrmRelease(rel)
+	s.Id = primitive.NilObjectID
+	err = validateReleaseInsert(s)
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+
+	// creating an index that exists does nothing
+	_, err = collection.Indexes().CreateOne(mgses.Ctx, mongo.IndexModel{
+		Keys:    bson.D{{"pluginId", 1}, {"version", 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+
+	_, err = collection.InsertOne(mgses.Ctx, s)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrReleaseExists
+	}
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// DeleteRelease removes the record of a version whose upload failed
+func (p *PluginsOrm) DeleteRelease(rel *api.Release) error {
+	mgses, err := getMongoSession()
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+	defer mgses.Cancel()
+
+	collection := mgses.Client.Database("plugins").Collection("releases")
+
+	s := apiToOrmRelease(rel)
+	err = validateReleaseInsert(s)
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+
+	_, err = collection.DeleteOne(mgses.Ctx, bson.D{{"pluginId", s.PluginId}, {"version", s.Version}})
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetReleases lists every recorded version of a plugin, newest upload first
+func (p *PluginsOrm) GetReleases(rel *api.Release) (*api.Releases, error) {
+	mgses, err := getMongoSession()
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return nil, err
+	}
+	defer mgses.Cancel()
+
+	collection := mgses.Client.Database("plugins").Collection("releases")
+
+	s := apiToOrmRelease(rel)
+	if s.PluginId == primitive.NilObjectID {
+		err = errors.New("plugin id required")
+		logger.ErrLog.Print(err.Error())
+		return nil, err
+	}
+
+	cur, err := collection.Find(mgses.Ctx, bson.D{{"pluginId", s.PluginId}}, options.Find().SetSort(bson.D{{"uploadedAt", -1}}))
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return nil, err
+	}
+
+	results := []release{}
+	err = cur.All(mgses.Ctx, &results)
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		return nil, err
+	}
+
+	// releases are usually uploaded by the same few users, so look each up once
+	uploaders := map[primitive.ObjectID]*api.User{}
+	final := &api.Releases{}
+	for _, v := range results {
+		r := ormToApiRelease(v)
+		if v.Uploader != primitive.NilObjectID {
+			u, ok := uploaders[v.Uploader]
+			if !ok {
+				if dbUser, err := NewUsersOrm().Get(&api.User{Id: v.Uploader.Hex()}); err == nil {
+					u = &api.User{Id: dbUser.Id, Username: dbUser.Username}
+				}
+				uploaders[v.Uploader] = u
+			}
+			if u != nil {
+				r.Uploader = u
+			}
+		}
+		final.Releases = append(final.Releases, r)
+	}
+
+	return final, nil
+}
+
+func validateReleaseInsert(rel release) error {
+	if rel.PluginId == primitive.NilObjectID || rel.Version == "" {
+		return errors.New("plugin id and version required")
+	}
+	return nil
+}
+
+func apiToOrmRelease(rel *api.Release) release {
+	if rel == nil {
+		return release{}
+	}
+	result := release{
+		Version:          rel.Version,
+		Size:             rel.Size_,
+		Sha256:           rel.Sha256,
+		UploadedAt:       rel.UploadedAt,
+		Channel:          rel.Channel,
+		MinecraftVersion: rel.MinecraftVersion,
+	}
+	if rel.Metadata != nil {
+		result.Metadata = &metadata{
+			Conflicts:  rel.Metadata.Conflicts,
+			Depend:     rel.Metadata.Depend,
+			SoftDepend: rel.Metadata.Softdepend,
+			LoadBefore: rel.Metadata.Loadbefore,
+		}
+	}
+
+	if rel.Id != "" {
+		id, err := primitive.ObjectIDFromHex(rel.Id)
+		if err == nil && id != primitive.NilObjectID {
+			result.Id = id
+		}
+	}
+	if rel.PluginId != "" {
+		id, err := primitive.ObjectIDFromHex(rel.PluginId)
+		if err == nil && id != primitive.NilObjectID {
+			result.PluginId = id
+		}
+	}
+	if rel.Uploader != nil {
+		id, err := primitive.ObjectIDFromHex(rel.Uploader.Id)
+		if err == nil && id != primitive.NilObjectID {
+			result.Uploader = id
+		}
+	}
+
+	return result
+}
+
+func ormToApiRelease(rel release) *api.Release {
+	result := &api.Release{
+		Id:               rel.Id.Hex(),
+		PluginId:         rel.PluginId.Hex(),
+		Version:          rel.Version,
+		Size_:            rel.Size,
+		Sha256:           rel.Sha256,
+		UploadedAt:       rel.UploadedAt,
+		Channel:          rel.Channel,
+		MinecraftVersion: rel.MinecraftVersion,
+	}
+	if rel.Uploader != primitive.NilObjectID {
+		result.Uploader = &api.User{Id: rel.Uploader.Hex()}
+	}
+	if rel.Metadata != nil {
+		result.Metadata = &api.PluginMetadata{
+			Conflicts:  rel.Metadata.Conflicts,
+			Depend:     rel.Metadata.Depend,
+			Softdepend: rel.Metadata.SoftDepend,
+			Loadbefore: rel.Metadata.LoadBefore,
+		}
+	}
+	return result
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/bennycio/bundle/api"
 	"github.com/bennycio/bundle/internal/db/orm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type pluginsServer struct {
@@ -49,6 +51,45 @@ func (s *pluginsServer) Paginate(ctx context.Context, req *api.PaginatePluginsRe
 	}, nil
 }
 
+func (s *pluginsServer) InsertRelease(ctx context.Context, req *api.Release) (*api.Empty, error) {
+	err := s.orm.InsertRelease(req)
+	if err == orm.ErrReleaseExists {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &api.Empty{}, nil
+}
+
+func (s *pluginsServer) DeleteRelease(ctx context.Context, req *api.Release) (*api.Empty, error) {
+	err := s.orm.DeleteRelease(req)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Empty{}, nil
+}
+
+func (s *pluginsServer) GetReleases(ctx context.Context, req *api.Release) (*api.Releases, error) {
+	return s.orm.GetReleases(req)
+}
+
+func (s *pluginsServer) UpdateYanked(ctx context.Context, req *api.Plugin) (*api.Empty, error) {
+	err := s.orm.UpdateYanked(req)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Empty{}, nil
+}
+
+func (s *pluginsServer) UpdateDeprecation(ctx context.Context, req *api.Plugin) (*api.Empty, error) {
+	err := s.orm.UpdateDeprecation(req)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Empty{}, nil
+}
+
 func newPluginsServer() *pluginsServer {
 	s := &pluginsServer{orm: orm.NewPluginsOrm()}
 	return s
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// ChecksumHeader carries the hex encoded SHA-256 digest of a whole plugin jar
+const ChecksumHeader = "X-Checksum-Sha256"
+
+// Download is a plugin jar that is streamed from the repository. Body holds
+// Length bytes of the jar starting at Offset, Size is the length of the whole
+// jar. Either length is -1 when it is not known
+type Download struct {
+	Body   io.ReadCloser
+	Offset int64
+	Length int64
+	Size   int64
+	Sha256 string
+}
+
+// RangeHeader builds the Range header that requests a jar from offset to its end
+func RangeHeader(offset int64) string {
+	return fmt.Sprintf("bytes=%d-", offset)
+}
+
+// WriteHeaders writes the status and headers of a download to a response,
+// a download that does not start at the beginning of the jar is written as
+// partial content
+func (d *Download) WriteHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("Content-Type", "application/java-archive")
+	h.Set("Accept-Ranges", "bytes")
+	if d.Sha256 != "" {
+		h.Set(ChecksumHeader, d.Sha256)
+	}
+	if d.Length >= 0 {
+		h.Set("Content-Length", strconv.FormatInt(d.Length, 10))
+	}
+
+	if d.Offset > 0 || (d.Size >= 0 && d.Length >= 0 && d.Length < d.Size) {
+		size := "*"
+		if d.Size >= 0 {
+			size = strconv.FormatInt(d.Size, 10)
+		}
+		h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%s", d.Offset, d.Offset+d.Length-1, size))
+		w.WriteHeader(http.StatusPartialContent)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// DownloadFromResponse reads a download from a successful response. The caller
+// is responsible for closing its Body
+func DownloadFromResponse(resp *http.Response) (*Download, error) {
+	d := &Download{
+		Body:   resp.Body,
+		Length: resp.ContentLength,
+		Size:   resp.ContentLength,
+		Sha256: resp.Header.Get(ChecksumHeader),
+	}
+
+	if resp.StatusCode == http.StatusPartialContent {
+		offset, size, err := ParseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return nil, err
+		}
+		d.Offset = offset
+		d.Size = size
+	}
+	return d, nil
+}
+
+// ParseContentRange reads the first byte and the complete length from a
+// Content-Range header such as "bytes 100-999/1000". The complete length is -1
+// when the header does not state it
+func ParseContentRange(header string) (int64, int64, error) {
+	var start, end int64
+	var size string
+	_, err := fmt.Sscanf(header, "bytes %d-%d/%s", &start, &end, &size)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid content range %q", header)
+	}
+	if size == "*" {
+		return start, -1, nil
+	}
+	total, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid content range %q", header)
+	}
+	return start, total, nil
+}
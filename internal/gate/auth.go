@@ -9,10 +9,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/bennycio/bundle/api"
 	"github.com/bennycio/bundle/internal"
 	"github.com/form3tech-oss/jwt-go"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type CustomClaims struct {
@@ -86,10 +84,7 @@ func simpleAuth(next http.Handler) http.Handler {
 
 func scopedAuth(next http.Handler, scopes ...string) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		authHeaderParts := strings.Split(r.Header.Get("Authorization"), " ")
-		token := authHeaderParts[1]
-
-		hasScope := checkScope(token, scopes...)
+		hasScope := checkScope(bearerToken(r), scopes...)
 
 		if !hasScope {
 			internal.WriteResponse(rw, "insufficient scope", http.StatusUnauthorized)
@@ -128,45 +123,6 @@ func checkScope(tokenString string, scopes ...string) bool {
 	return hasScope
 }
 
-func basicAuth(next http.Handler, methods ...string) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if internal.Contains(methods, r.Method) {
-
-			err := r.ParseMultipartForm(10 << 20)
-
-			if err != nil {
-				err = r.ParseForm()
-				if err != nil {
-					http.Error(w, "invalid auth", http.StatusBadRequest)
-					return
-				}
-			}
-
-			gs := NewGateService("", "")
-			un := r.FormValue("username")
-
-			user := &api.User{
-				Username: un,
-			}
-			dbUser, err := gs.GetUser(user)
-
-			if err != nil {
-				http.Error(w, "invalid user", http.StatusBadRequest)
-				return
-			}
-
-			err = bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(r.FormValue("password")))
-
-			if err != nil {
-				http.Error(w, "incorrect password", http.StatusUnauthorized)
-				return
-			}
-
-		}
-		next.ServeHTTP(w, r)
-	})
-}
-
 func encryptKey(key string) (string, error) {
 
 	c, err := aes.NewCipher([]byte(os.Getenv("AES_KEY")))
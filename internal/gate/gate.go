@@ -16,17 +16,25 @@ func NewGateServer() *http.Server {
 	readmesHandler := http.HandlerFunc(readmesHandlerFunc)
 	sessionsHandler := http.HandlerFunc(sessionHandlerFunc)
 	changelogsHandler := http.HandlerFunc(changelogHandlerFunc)
+	tokensHandler := http.HandlerFunc(tokensHandlerFunc)
+	yanksHandler := http.HandlerFunc(yanksHandlerFunc)
+	deprecationsHandler := http.HandlerFunc(deprecationsHandlerFunc)
+	releasesHandler := http.HandlerFunc(releasesHandlerFunc)
 
 	checkoutCompleteHandler := http.HandlerFunc(checkoutCompleteHandlerFunc)
 
 	mux.Handle("/api/plugins", pluginsHandler)
+	mux.Handle("/api/plugins/yanks", serviceOrUserAuth(yanksHandler, "plugins", ScopePublish))
+	mux.Handle("/api/plugins/releases", releasesHandler)
+	mux.Handle("/api/plugins/deprecations", serviceOrUserAuth(deprecationsHandler, "plugins", ScopePublish))
 	mux.Handle("/api/purchases/complete", checkoutCompleteHandler)
-	mux.Handle("/api/changelogs", basicAuth(changelogsHandler, http.MethodPost))
+	mux.Handle("/api/changelogs", userAuth(changelogsHandler, ScopePublish, http.MethodPost))
 	mux.Handle("/api/users", scopedAuth(usersHandler, "users"))
-	mux.Handle("/api/readmes", basicAuth(readmesHandler, http.MethodPost, http.MethodPatch))
+	mux.Handle("/api/readmes", userAuth(readmesHandler, ScopePublish, http.MethodPost, http.MethodPatch))
 	mux.Handle("/api/sessions", scopedAuth(sessionsHandler, "sessions"))
-	mux.Handle("/api/repo/plugins", basicAuth(repoPluginsHandler, http.MethodPost))
-	mux.Handle("/api/repo/thumbnails", scopedAuth(repoThumbnailsHandler, "thumbnails"))
+	mux.Handle("/api/repo/plugins", userAuth(repoPluginsHandler, ScopePublish, http.MethodPost))
+	mux.Handle("/api/tokens", tokensHandler)
+	mux.Handle("/api/repo/thumbnails", serviceOrUserAuth(repoThumbnailsHandler, "thumbnails", ScopePublish))
 
 	return internal.MakeServerFromMux(mux)
 }
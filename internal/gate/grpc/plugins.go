@@ -2,18 +2,29 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/bennycio/bundle/api"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// ErrReleaseExists is returned when a version of a plugin is recorded again
+var ErrReleaseExists = errors.New("release already exists, versions cannot be replaced")
+
 type pluginsGrpcClient interface {
 	Get(req *api.Plugin) (*api.Plugin, error)
 	Update(req *api.Plugin) error
 	Insert(req *api.Plugin) error
 	Paginate(req *api.PaginatePluginsRequest) (*api.PaginatePluginsResponse, error)
+	InsertRelease(req *api.Release) error
+	GetReleases(req *api.Release) (*api.Releases, error)
+	DeleteRelease(req *api.Release) error
+	UpdateYanked(req *api.Plugin) error
+	UpdateDeprecation(req *api.Plugin) error
 }
 
 type pluginsGrpcClientImpl struct {
@@ -108,3 +119,101 @@ func (p *pluginsGrpcClientImpl) Paginate(req *api.PaginatePluginsRequest) (*api.
 	}
 	return results, nil
 }
+func (p *pluginsGrpcClientImpl) InsertRelease(req *api.Release) error {
+	creds, err := getCert()
+	if err != nil {
+		return err
+	}
+	addr := fmt.Sprintf("%v:%v", p.Host, p.Port)
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := api.NewPluginsServiceClient(conn)
+
+	_, err = client.InsertRelease(context.Background(), req)
+	if status.Code(err) == codes.AlreadyExists {
+		return ErrReleaseExists
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (p *pluginsGrpcClientImpl) GetReleases(req *api.Release) (*api.Releases, error) {
+	creds, err := getCert()
+	if err != nil {
+		return nil, err
+	}
+	addr := fmt.Sprintf("%v:%v", p.Host, p.Port)
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := api.NewPluginsServiceClient(conn)
+
+	results, err := client.GetReleases(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+func (p *pluginsGrpcClientImpl) UpdateYanked(req *api.Plugin) error {
+	creds, err := getCert()
+	if err != nil {
+		return err
+	}
+	addr := fmt.Sprintf("%v:%v", p.Host, p.Port)
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := api.NewPluginsServiceClient(conn)
+
+	_, err = client.UpdateYanked(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (p *pluginsGrpcClientImpl) UpdateDeprecation(req *api.Plugin) error {
+	creds, err := getCert()
+	if err != nil {
+		return err
+	}
+	addr := fmt.Sprintf("%v:%v", p.Host, p.Port)
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := api.NewPluginsServiceClient(conn)
+
+	_, err = client.UpdateDeprecation(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+func (p *pluginsGrpcClientImpl) DeleteRelease(req *api.Release) error {
+	creds, err := getCert()
+	if err != nil {
+		return err
+	}
+	addr := fmt.Sprintf("%v:%v", p.Host, p.Port)
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := api.NewPluginsServiceClient(conn)
+
+	_, err = client.DeleteRelease(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package gate
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/internal"
+	"github.com/bennycio/bundle/internal/gate/grpc"
+)
+
+func releasesHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	dbcl := grpc.NewPluginClient("", "")
+
+	switch r.Method {
+	case http.MethodGet:
+		err := r.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		pluginId := r.FormValue("pluginId")
+		if pluginId == "" {
+			name := r.FormValue("name")
+			if name == "" {
+				http.Error(w, "specify a plugin", http.StatusBadRequest)
+				return
+			}
+			dbPl, err := dbcl.Get(&api.Plugin{Name: name})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			pluginId = dbPl.Id
+		}
+
+		releases, err := dbcl.GetReleases(&api.Release{PluginId: pluginId})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		asJSON, err := json.Marshal(releases)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		internal.WriteResponse(w, string(asJSON), http.StatusOK)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
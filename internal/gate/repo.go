@@ -1,20 +1,26 @@
 package gate
 
 import (
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bennycio/bundle/api"
 	"github.com/bennycio/bundle/internal/gate/grpc"
 	"github.com/bennycio/bundle/internal/repo"
+	"github.com/bennycio/bundle/internal/version"
+	"github.com/bennycio/bundle/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func repoPluginsHandlerFunc(w http.ResponseWriter, r *http.Request) {
-	repo := repo.NewRepoService("", "")
+	rs := repo.NewRepoService("", "")
 	dbcl := grpc.NewPluginClient("", "")
-	uscl := grpc.NewUserClient("", "")
 
 	switch r.Method {
 	case http.MethodGet:
@@ -39,17 +45,13 @@ func repoPluginsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 		if dbPl.Premium != nil {
 			if dbPl.Premium.Price > 0 {
-				uclient := grpc.NewUserClient("", "")
-				user := r.FormValue("user")
-				u := &api.User{}
-				err = json.Unmarshal([]byte(user), u)
-				if err != nil {
-					http.Error(w, err.Error(), http.StatusBadRequest)
+				if bearerToken(r) == "" {
+					http.Error(w, "log in to download premium plugins", http.StatusUnauthorized)
 					return
 				}
-				dbUser, err := uclient.Get(u)
+				dbUser, status, err := tokenUser(r, ScopeDownload)
 				if err != nil {
-					http.Error(w, err.Error(), http.StatusBadRequest)
+					http.Error(w, err.Error(), status)
 					return
 				}
 				if dbPl.Author.Id != dbUser.Id {
@@ -73,29 +75,46 @@ func repoPluginsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 		if version != "latest" && version != "" {
 			dbPl.Version = version
+		} else {
+			// yanked versions are only served when they are asked for by name
+			latest, status, err := latestVersion(dbPl)
+			if err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+			dbPl.Version = latest
 		}
-		pl, err := repo.DownloadPlugin(dbPl)
+		dl, err := rs.DownloadPlugin(dbPl, r.Header.Get("Range"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		w.WriteHeader(http.StatusOK)
-		w.Write(pl)
+		defer dl.Body.Close()
+
+		dl.WriteHeaders(w)
+		if _, err := io.Copy(w, dl.Body); err != nil {
+			logger.ErrLog.Print(err.Error())
+		}
 		return
 
 	case http.MethodPost:
 
+		// an upload that is too large is rejected while it is read rather than
+		// after all of it was buffered
+		r.Body = http.MaxBytesReader(w, r.Body, maxJarSize+maxFormOverhead)
 		err := r.ParseMultipartForm(32 << 20)
 		if err != nil {
+			if strings.Contains(err.Error(), "request body too large") {
+				verr := &ValidationError{}
+				verr.add("plugin", "jar is larger than %d MB", maxJarSize>>20)
+				writeValidationError(w, verr)
+				return
+			}
 			fmt.Println(err.Error())
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		user := &api.User{
-			Username: r.FormValue("username"),
-			Password: r.FormValue("password"),
-		}
 		plugin := &api.Plugin{
 			Name:        r.FormValue("name"),
 			Version:     r.FormValue("version"),
@@ -106,54 +125,192 @@ func repoPluginsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			plugin.Category = api.Category(cat)
 		}
 
-		dbUser, err := uscl.Get(user)
+		if t, err := strconv.Atoi(r.FormValue("type")); err == nil {
+			plugin.Type = api.ArtifactType(t)
+		}
+
+		plugin.Channel = version.Stable
+		if ch := r.FormValue("channel"); ch != "" {
+			channel, ok := version.ParseChannel(ch)
+			if !ok {
+				verr := &ValidationError{}
+				verr.add("channel", "%s is not one of %s", ch, strings.Join(version.Channels, ", "))
+				writeValidationError(w, verr)
+				return
+			}
+			plugin.Channel = channel
+		}
+		plugin.Channels = map[string]string{plugin.Channel: plugin.Version}
+
+		if price, err := strconv.Atoi(r.FormValue("price")); err == nil {
+			plugin.Premium = &api.Premium{Price: int32(price)}
+		}
+
+		file, h, err := r.FormFile("plugin")
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeValidationError(w, &ValidationError{Errors: []FieldError{{Field: "plugin", Message: "missing jar"}}})
 			return
 		}
+		defer file.Close()
 
+		desc, verr := validateJar(file, h.Size, plugin)
+		if verr != nil {
+			writeValidationError(w, verr)
+			return
+		}
+
+		// the dependencies are what the jar declares, not what the client says
+		plugin.Metadata = &api.PluginMetadata{}
+		if desc != nil {
+			plugin.Metadata.Conflicts = desc.Conflicts
+			plugin.Metadata.Depend = desc.Depend
+			plugin.Metadata.Softdepend = desc.SoftDepend
+			plugin.Metadata.Loadbefore = desc.LoadBefore
+		}
+
+		hash := sha256.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		digest := hex.EncodeToString(hash.Sum(nil))
+
+		dbUser := authenticatedUser(r)
 		plugin.Author = dbUser
 
 		dbPlIni, err := dbcl.Get(plugin)
+		exists := err == nil
 
-		if err == nil {
+		if exists {
 			if dbUser.Id != dbPlIni.Author.Id {
 				http.Error(w, "cannot update another author's plugin", http.StatusUnauthorized)
 				return
-			} else {
-				err = dbcl.Update(plugin)
-				if err != nil {
-					http.Error(w, err.Error(), http.StatusBadRequest)
-					return
-				}
 			}
-		} else {
-			err = dbcl.Insert(plugin)
+			if dbPlIni.Type != plugin.Type {
+				verr := &ValidationError{}
+				verr.add("type", "%s is uploaded as %s, not %s", dbPlIni.Name, dbPlIni.Type, plugin.Type)
+				writeValidationError(w, verr)
+				return
+			}
+
+			changelogs, _ := grpc.NewChangelogsClient("", "").GetAll(&api.Changelog{PluginId: dbPlIni.Id})
+			releases, err := dbcl.GetReleases(&api.Release{PluginId: dbPlIni.Id})
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if hasVersion(dbPlIni, changelogs, releases, plugin.Version) {
+				http.Error(w, fmt.Sprintf("%s %s already exists, versions cannot be replaced so release a new version instead", dbPlIni.Name, plugin.Version), http.StatusConflict)
 				return
 			}
+			plugin.Id = dbPlIni.Id
+		} else {
+			// the id is picked before anything is stored so that the jar can be
+			// stored under it before the plugin is inserted
+			plugin.Id = primitive.NewObjectID().Hex()
 		}
 
-		dbPlugin, err := dbcl.Get(plugin)
+		// the jar is staged and verified before anything points at it. The
+		// release is then recorded, which only one upload of a version can do,
+		// before the jar is committed. If anything fails, what was done so far
+		// is undone so that the upload can be retried
+		stored := &api.Plugin{Id: plugin.Id, Author: dbUser, Version: plugin.Version}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		key, err := rs.StagePlugin(stored, file, digest)
 		if err != nil {
-			fmt.Println(err.Error())
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusBadGateway)
 			return
 		}
 
-		file, _, err := r.FormFile("plugin")
+		release := &api.Release{
+			PluginId:   plugin.Id,
+			Version:    plugin.Version,
+			Size_:      h.Size,
+			Sha256:     digest,
+			UploadedAt: time.Now().Unix(),
+			Channel:    plugin.Channel,
+			Uploader:   &api.User{Id: dbUser.Id},
+			// kept with the release so that installs of older versions
+			// resolve the dependencies of the version they install
+			Metadata: plugin.Metadata,
+		}
+		if desc != nil {
+			release.MinecraftVersion = desc.APIVersion
+		}
+		err = dbcl.InsertRelease(release)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			logErr(rs.AbortPlugin(stored, key))
+			if err == grpc.ErrReleaseExists {
+				http.Error(w, fmt.Sprintf("%s %s already exists, versions cannot be replaced so release a new version instead", plugin.Name, plugin.Version), http.StatusConflict)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		err = repo.UploadPlugin(dbUser, dbPlugin, file)
+
+		err = rs.CommitPlugin(stored, key)
 		if err != nil {
-			fmt.Println(err.Error())
+			logErr(rs.AbortPlugin(stored, key))
+			logErr(dbcl.DeleteRelease(release))
+			if err == repo.ErrVersionExists {
+				http.Error(w, fmt.Sprintf("%s %s already exists, versions cannot be replaced so release a new version instead", plugin.Name, plugin.Version), http.StatusConflict)
+				return
+			}
 			http.Error(w, err.Error(), http.StatusBadGateway)
 			return
 		}
 
+		if exists {
+			if dbPlIni.Metadata != nil {
+				plugin.Metadata.Downloads = dbPlIni.Metadata.Downloads
+			}
+			if dbPlIni.Premium != nil {
+				if plugin.Premium == nil {
+					plugin.Premium = &api.Premium{Price: dbPlIni.Premium.Price}
+				}
+				plugin.Premium.Purchases = dbPlIni.Premium.Purchases
+			}
+			for k, v := range dbPlIni.Channels {
+				if k != plugin.Channel {
+					plugin.Channels[k] = v
+				}
+			}
+			update := plugin
+			if plugin.Channel != version.Stable {
+				// the plugin keeps its newest stable version, which is what
+				// latest resolves to, only the channels are updated
+				update = dbPlIni
+				update.Channels = plugin.Channels
+				update.LastUpdated = 0
+			}
+			err = dbcl.Update(update)
+		} else {
+			if plugin.Channel != version.Stable {
+				// without a stable release latest has nothing to resolve to,
+				// the version is only in the channels
+				plugin.Version = ""
+				plugin.Channel = ""
+			}
+			err = dbcl.Insert(plugin)
+		}
+		if err != nil {
+			logErr(rs.RemovePlugin(stored))
+			logErr(dbcl.DeleteRelease(release))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	}
+}
+
+// logErr logs the error of undoing part of an upload, which leaves garbage
+// behind at worst
+func logErr(err error) {
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
 	}
 }
 
@@ -165,8 +322,17 @@ func repoThumbnailsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 	case http.MethodPost:
 
+		// an upload that is too large is rejected while it is read rather than
+		// after all of it was buffered
+		r.Body = http.MaxBytesReader(w, r.Body, maxJarSize+maxFormOverhead)
 		err := r.ParseMultipartForm(32 << 20)
 		if err != nil {
+			if strings.Contains(err.Error(), "request body too large") {
+				verr := &ValidationError{}
+				verr.add("plugin", "jar is larger than %d MB", maxJarSize>>20)
+				writeValidationError(w, verr)
+				return
+			}
 			fmt.Println(err.Error())
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -179,10 +345,13 @@ func repoThumbnailsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			Id: r.FormValue("plugin"),
 		}
 
-		dbUser, err := gs.GetUser(user)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+		dbUser := authenticatedUser(r)
+		if dbUser == nil {
+			dbUser, err = gs.GetUser(user)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 		}
 
 		file, h, err := r.FormFile("thumbnail")
@@ -210,6 +379,11 @@ func repoThumbnailsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			if dbPlugin.Author == nil || dbPlugin.Author.Id != dbUser.Id {
+				http.Error(w, "you are not the author of this plugin", http.StatusForbidden)
+				return
+			}
+
 			err = repo.UploadThumbnail(dbUser, dbPlugin, file)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusBadGateway)
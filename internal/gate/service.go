@@ -10,13 +10,15 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/bennycio/bundle/api"
 	"github.com/bennycio/bundle/internal"
 )
 
 type gateService interface {
-	DownloadPlugin(plugin *api.Plugin, user *api.User) ([]byte, error)
+	DownloadPlugin(plugin *api.Plugin, offset int64) (*internal.Download, error)
+	PluginDownloadUrl(plugin *api.Plugin) (string, error)
 	UploadPlugin(user *api.User, plugin *api.Plugin, data io.Reader) error
 	UploadThumbnail(user *api.User, plugin *api.Plugin, data io.Reader) error
 	PaginatePlugins(req *api.PaginatePluginsRequest) ([]*api.Plugin, error)
@@ -34,11 +36,22 @@ type gateService interface {
 	DeleteSession(ses *api.Session) error
 	GetChangelog(ch *api.Changelog) (*api.Changelog, error)
 	GetChangelogs(ch *api.Changelog) (*api.Changelogs, error)
+	GetReleases(plugin *api.Plugin) (*api.Releases, error)
 	InsertChangelog(user *api.User, ch *api.Changelog) error
+	CreateToken(user *api.User, scopes []string) (*ApiToken, error)
+	GetToken() (*ApiToken, error)
+	RevokeToken() error
+	YankVersion(user *api.User, plugin *api.Plugin, reason string) error
+	UnyankVersion(user *api.User, plugin *api.Plugin) error
+	DeprecatePlugin(user *api.User, plugin *api.Plugin, dep *api.Deprecation) error
+	UndeprecatePlugin(user *api.User, plugin *api.Plugin) error
 }
 type gateServiceImpl struct {
 	Host string
 	Port string
+	// Token is the API token that requests which act on behalf of a user are
+	// sent with, the username and password of the user are sent when it is empty
+	Token string
 }
 
 func NewGateService(host string, port string) gateService {
@@ -54,34 +67,75 @@ func NewGateService(host string, port string) gateService {
 	}
 }
 
-func (g *gateServiceImpl) DownloadPlugin(plugin *api.Plugin, user *api.User) ([]byte, error) {
+// NewGateServiceWithToken returns a gate service that authenticates as the
+// user of an API token
+func NewGateServiceWithToken(host string, port string, token string) gateService {
+	g := NewGateService(host, port).(*gateServiceImpl)
+	g.Token = token
+	return g
+}
+
+func (g *gateServiceImpl) authorize(req *http.Request) {
+	if g.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+}
+
+// writeCredentials adds the username and password of a user to a form unless
+// the service has an API token
+func (g *gateServiceImpl) writeCredentials(user *api.User, set func(key, value string)) error {
+	if g.Token != "" {
+		return nil
+	}
+	if user == nil || user.Username == "" || user.Password == "" {
+		return errors.New("log in first")
+	}
+	set("username", user.Username)
+	set("password", user.Password)
+	return nil
+}
 
+func (g *gateServiceImpl) PluginDownloadUrl(plugin *api.Plugin) (string, error) {
 	scheme := "https://"
 	u, err := url.Parse(fmt.Sprintf("%s%s:%s/api/repo/plugins", scheme, g.Host, g.Port))
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	usJson, err := json.Marshal(user)
-	if err != nil {
-		return nil, err
-	}
 	q := u.Query()
 	q.Add("name", plugin.Name)
 	q.Add("version", plugin.Version)
-	q.Add("user", string(usJson))
 	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// DownloadPlugin opens a stream of a plugin jar starting at the given byte
+// offset. The caller is responsible for closing the Body of the download
+func (g *gateServiceImpl) DownloadPlugin(plugin *api.Plugin, offset int64) (*internal.Download, error) {
+
+	addr, err := g.PluginDownloadUrl(plugin)
+	if err != nil {
+		return nil, err
+	}
 
 	client := internal.NewBasicClient()
 
-	resp, err := client.Get(u.String())
+	req, err := http.NewRequest(http.MethodGet, addr, nil)
 	if err != nil {
 		return nil, err
 	}
+	g.authorize(req)
+	if offset > 0 {
+		req.Header.Set("Range", internal.RangeHeader(offset))
+	}
 
-	defer resp.Body.Close()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
 
 	if internal.IsRespError(resp) {
+		defer resp.Body.Close()
 		buf := &bytes.Buffer{}
 		_, err = io.Copy(buf, resp.Body)
 		if err != nil {
@@ -90,12 +144,12 @@ func (g *gateServiceImpl) DownloadPlugin(plugin *api.Plugin, user *api.User) ([]
 		return nil, errors.New(buf.String())
 	}
 
-	bs := &bytes.Buffer{}
-	_, err = io.Copy(bs, resp.Body)
+	dl, err := internal.DownloadFromResponse(resp)
 	if err != nil {
+		resp.Body.Close()
 		return nil, err
 	}
-	return bs.Bytes(), nil
+	return dl, nil
 }
 
 func (g *gateServiceImpl) UploadPlugin(user *api.User, plugin *api.Plugin, data io.Reader) error {
@@ -108,22 +162,25 @@ func (g *gateServiceImpl) UploadPlugin(user *api.User, plugin *api.Plugin, data
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
-	if user != nil && plugin != nil {
-		if user.Username == "" || user.Password == "" || plugin.Name == "" || plugin.Version == "" {
-			return errors.New("missing required fields")
-		}
-	} else {
-		return errors.New("specify a user and plugin")
+	if plugin == nil {
+		return errors.New("specify a plugin")
+	}
+	if plugin.Name == "" || plugin.Version == "" {
+		return errors.New("missing required fields")
 	}
 
-	writer.WriteField("username", user.Username)
-	writer.WriteField("author", user.Username)
-	writer.WriteField("password", user.Password)
+	if err := g.writeCredentials(user, func(key, value string) { writer.WriteField(key, value) }); err != nil {
+		return err
+	}
 	writer.WriteField("name", plugin.Name)
 	writer.WriteField("version", plugin.Version)
 	writer.WriteField("description", plugin.Description)
 	writer.WriteField("category", fmt.Sprint(plugin.Category))
-
+	writer.WriteField("type", fmt.Sprint(int32(plugin.Type)))
+	writer.WriteField("channel", plugin.Channel)
+	if plugin.Premium != nil {
+		writer.WriteField("price", fmt.Sprint(plugin.Premium.Price))
+	}
 	part, err := writer.CreateFormFile("plugin", plugin.Name)
 	if err != nil {
 		return err
@@ -141,13 +198,28 @@ func (g *gateServiceImpl) UploadPlugin(user *api.User, plugin *api.Plugin, data
 	}
 
 	client := internal.NewBasicClient()
-	resp, err := client.Post(u.String(), writer.FormDataContentType(), body)
+	req, err := http.NewRequest(http.MethodPost, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	g.authorize(req)
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		verr := &ValidationError{}
+		if err := json.NewDecoder(resp.Body).Decode(verr); err != nil {
+			return err
+		}
+		return verr
+	}
+
 	if internal.IsRespError(resp) {
 		buf := &bytes.Buffer{}
 		_, err = io.Copy(buf, resp.Body)
@@ -173,6 +245,9 @@ func (g *gateServiceImpl) UploadThumbnail(user *api.User, plugin *api.Plugin, da
 	if user == nil && plugin == nil {
 		return errors.New("specify a user or plugin")
 	}
+	if user == nil && g.Token == "" {
+		return errors.New("specify a user or log in first")
+	}
 	if plugin != nil {
 		if plugin.Id == "" {
 			return errors.New("specify a plugin id")
@@ -210,13 +285,16 @@ func (g *gateServiceImpl) UploadThumbnail(user *api.User, plugin *api.Plugin, da
 		return err
 	}
 
-	accessToken, err := newAuthToken("thumbnails")
-	if err != nil {
-		return err
-	}
-
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Authorization", "Bearer "+accessToken)
+	if g.Token != "" {
+		g.authorize(req)
+	} else {
+		accessToken, err := newAuthToken("thumbnails")
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -418,13 +496,14 @@ func (g *gateServiceImpl) InsertReadme(user *api.User, readme *api.Readme) error
 	client := internal.NewBasicClient()
 
 	values := url.Values{}
-	values.Set("username", user.Username)
-	values.Set("password", user.Password)
+	if err := g.writeCredentials(user, values.Set); err != nil {
+		return err
+	}
 	values.Set("plugin_id", readme.Plugin.Id)
 	values.Set("plugin_name", readme.Plugin.Name)
 	values.Set("text", readme.Text)
 
-	resp, err := client.PostForm(u.String(), values)
+	resp, err := g.postForm(client, u.String(), values)
 	if err != nil {
 		return err
 	}
@@ -844,12 +923,13 @@ func (g *gateServiceImpl) InsertChangelog(user *api.User, ch *api.Changelog) err
 	}
 
 	values.Set("changelog", string(asJSON))
-	values.Set("username", user.Username)
-	values.Set("password", user.Password)
+	if err := g.writeCredentials(user, values.Set); err != nil {
+		return err
+	}
 
 	client := internal.NewBasicClient()
 
-	resp, err := client.PostForm(u.String(), values)
+	resp, err := g.postForm(client, u.String(), values)
 	if err != nil {
 		return err
 	}
@@ -960,3 +1040,269 @@ func (g *gateServiceImpl) GetChangelogs(ch *api.Changelog) (*api.Changelogs, err
 	}
 	return result, nil
 }
+
+func (g *gateServiceImpl) postForm(client http.Client, addr string, values url.Values) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, addr, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	g.authorize(req)
+	return client.Do(req)
+}
+
+// CreateToken trades the username and password of a user for an API token
+// with the given scopes, every scope when none are given
+func (g *gateServiceImpl) CreateToken(user *api.User, scopes []string) (*ApiToken, error) {
+	scheme := "https://"
+
+	u, err := url.Parse(fmt.Sprintf("%s%s:%s/api/tokens", scheme, g.Host, g.Port))
+	if err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Set("username", user.Username)
+	values.Set("password", user.Password)
+	for _, v := range scopes {
+		values.Add("scope", v)
+	}
+
+	client := internal.NewBasicClient()
+	resp, err := client.PostForm(u.String(), values)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readToken(resp)
+}
+
+// GetToken describes the API token of the service and the user it belongs to
+func (g *gateServiceImpl) GetToken() (*ApiToken, error) {
+	scheme := "https://"
+
+	u, err := url.Parse(fmt.Sprintf("%s%s:%s/api/tokens", scheme, g.Host, g.Port))
+	if err != nil {
+		return nil, err
+	}
+
+	client := internal.NewBasicClient()
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	g.authorize(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readToken(resp)
+}
+
+// RevokeToken revokes the API token of the service
+func (g *gateServiceImpl) RevokeToken() error {
+	scheme := "https://"
+
+	u, err := url.Parse(fmt.Sprintf("%s%s:%s/api/tokens", scheme, g.Host, g.Port))
+	if err != nil {
+		return err
+	}
+
+	client := internal.NewBasicClient()
+	req, err := http.NewRequest(http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	g.authorize(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if internal.IsRespError(resp) {
+		buf := &bytes.Buffer{}
+		_, err = io.Copy(buf, resp.Body)
+		if err != nil {
+			return err
+		}
+		return errors.New(buf.String())
+	}
+	return nil
+}
+
+func readToken(resp *http.Response) (*ApiToken, error) {
+	bs := &bytes.Buffer{}
+	_, err := io.Copy(bs, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if internal.IsRespError(resp) {
+		return nil, errors.New(bs.String())
+	}
+
+	result := &ApiToken{}
+	err = json.Unmarshal(bs.Bytes(), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetReleases lists every recorded version of a plugin, by its id or name,
+// newest upload first
+func (g *gateServiceImpl) GetReleases(plugin *api.Plugin) (*api.Releases, error) {
+	scheme := "https://"
+
+	u, err := url.Parse(fmt.Sprintf("%s%s:%s/api/plugins/releases", scheme, g.Host, g.Port))
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	if plugin.Id != "" {
+		q.Add("pluginId", plugin.Id)
+	} else {
+		q.Add("name", plugin.Name)
+	}
+	u.RawQuery = q.Encode()
+	client := internal.NewBasicClient()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if internal.IsRespError(resp) {
+		buf := &bytes.Buffer{}
+		_, err = io.Copy(buf, resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New(buf.String())
+	}
+
+	result := &api.Releases{}
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// YankVersion hides the version of a plugin from version resolution, it can
+// still be downloaded when it is pinned exactly. Without a token the request
+// is made for the given user by this service
+func (g *gateServiceImpl) YankVersion(user *api.User, plugin *api.Plugin, reason string) error {
+	values := userValues(user)
+	values.Set("name", plugin.Name)
+	values.Set("version", plugin.Version)
+	values.Set("reason", reason)
+	return g.sendForm(http.MethodPost, "/api/plugins/yanks", values)
+}
+
+// UnyankVersion makes a yanked version of a plugin resolvable again
+func (g *gateServiceImpl) UnyankVersion(user *api.User, plugin *api.Plugin) error {
+	values := userValues(user)
+	values.Set("name", plugin.Name)
+	values.Set("version", plugin.Version)
+	return g.sendForm(http.MethodDelete, "/api/plugins/yanks", values)
+}
+
+// DeprecatePlugin marks a plugin as no longer maintained, optionally pointing
+// to the plugin that replaces it
+func (g *gateServiceImpl) DeprecatePlugin(user *api.User, plugin *api.Plugin, dep *api.Deprecation) error {
+	values := userValues(user)
+	values.Set("name", plugin.Name)
+	values.Set("reason", dep.Reason)
+	values.Set("replacement", dep.Replacement)
+	return g.sendForm(http.MethodPost, "/api/plugins/deprecations", values)
+}
+
+// UndeprecatePlugin takes back the deprecation of a plugin
+func (g *gateServiceImpl) UndeprecatePlugin(user *api.User, plugin *api.Plugin) error {
+	values := userValues(user)
+	values.Set("name", plugin.Name)
+	return g.sendForm(http.MethodDelete, "/api/plugins/deprecations", values)
+}
+
+// userValues starts the form of a request made for a user by this service
+func userValues(user *api.User) url.Values {
+	values := url.Values{}
+	if user != nil {
+		values.Set("user", user.Id)
+	}
+	return values
+}
+
+// sendForm sends a form to the gate with the API token of the service, or
+// signed by this service when there is none. The form of a DELETE request
+// goes in its query
+func (g *gateServiceImpl) sendForm(method string, path string, values url.Values) error {
+	scheme := "https://"
+
+	u, err := url.Parse(fmt.Sprintf("%s%s:%s%s", scheme, g.Host, g.Port, path))
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader
+	if method == http.MethodDelete {
+		u.RawQuery = values.Encode()
+	} else {
+		body = strings.NewReader(values.Encode())
+	}
+
+	client := internal.NewBasicClient()
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if g.Token != "" {
+		g.authorize(req)
+	} else {
+		accessToken, err := newAuthToken("plugins")
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		verr := &ValidationError{}
+		if err := json.NewDecoder(resp.Body).Decode(verr); err != nil {
+			return err
+		}
+		return verr
+	}
+
+	if internal.IsRespError(resp) {
+		buf := &bytes.Buffer{}
+		_, err = io.Copy(buf, resp.Body)
+		if err != nil {
+			return err
+		}
+		return errors.New(buf.String())
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package gate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/internal"
+	"github.com/bennycio/bundle/internal/gate/grpc"
+	"github.com/form3tech-oss/jwt-go"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// ScopeDownload lets an API token download the premium plugins its user owns
+	ScopeDownload = "download"
+	// ScopePublish lets an API token upload plugins, readmes and changelogs
+	ScopePublish = "publish"
+
+	apiTokenLifetime = 90 * 24 * time.Hour
+)
+
+// ApiTokenScopes are the scopes that a user can ask for when logging in
+var ApiTokenScopes = []string{ScopeDownload, ScopePublish}
+
+// ApiToken is a revocable token that the CLI sends as a Bearer header instead
+// of the password of its user
+type ApiToken struct {
+	Token     string   `json:"token,omitempty"`
+	Username  string   `json:"username"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt int64    `json:"expiresAt"`
+}
+
+type userKey struct{}
+
+// newApiToken starts a session for an API token and signs a token that points
+// to it, deleting the session revokes the token
+func newApiToken(user *api.User, scopes []string) (*ApiToken, error) {
+	expiresAt := time.Now().Add(apiTokenLifetime).Unix()
+
+	sessions := grpc.NewSessionsClient("", "")
+	ses, err := sessions.Insert(&api.Session{UserId: user.Id, ExpiresAt: expiresAt})
+	if err != nil {
+		return nil, err
+	}
+
+	claims := CustomClaims{
+		Scopes: scopes,
+		StandardClaims: jwt.StandardClaims{
+			Id:        ses.Id,
+			Subject:   user.Id,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: expiresAt,
+			Issuer:    "bundle",
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApiToken{
+		Token:     signedToken,
+		Username:  user.Username,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// parseApiToken checks the signature and expiry of an API token and that it
+// has not been revoked
+func parseApiToken(tokenString string) (*CustomClaims, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&CustomClaims{},
+		func(token *jwt.Token) (interface{}, error) {
+			return []byte(secret), nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*CustomClaims)
+	if !ok || claims.Id == "" || claims.Subject == "" {
+		return nil, errors.New("not an API token")
+	}
+
+	sessions := grpc.NewSessionsClient("", "")
+	ses, err := sessions.Get(&api.Session{Id: claims.Id})
+	if err != nil || ses.UserId != claims.Subject {
+		return nil, errors.New("revoked token")
+	}
+	return claims, nil
+}
+
+func bearerToken(r *http.Request) string {
+	authHeaderParts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(authHeaderParts) != 2 || !strings.EqualFold(authHeaderParts[0], "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(authHeaderParts[1])
+}
+
+// requestUser finds the user that sent a request, either by the API token in
+// its Bearer header, which must have the given scope, or by the username and
+// password of its form
+func requestUser(r *http.Request, scope string) (*api.User, int, error) {
+	if bearerToken(r) != "" {
+		return tokenUser(r, scope)
+	}
+	return passwordUser(r)
+}
+
+func tokenUser(r *http.Request, scope string) (*api.User, int, error) {
+	claims, err := parseApiToken(bearerToken(r))
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+	if !internal.Contains(claims.Scopes, scope) {
+		return nil, http.StatusForbidden, errors.New("token is missing the " + scope + " scope")
+	}
+	uscl := grpc.NewUserClient("", "")
+	dbUser, err := uscl.Get(&api.User{Id: claims.Subject})
+	if err != nil {
+		return nil, http.StatusUnauthorized, errors.New("invalid user")
+	}
+	return dbUser, http.StatusOK, nil
+}
+
+func passwordUser(r *http.Request) (*api.User, int, error) {
+	un := r.FormValue("username")
+	if un == "" {
+		return nil, http.StatusUnauthorized, errors.New("missing credentials")
+	}
+	uscl := grpc.NewUserClient("", "")
+	dbUser, err := uscl.Get(&api.User{Username: un})
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("invalid user")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(r.FormValue("password"))); err != nil {
+		return nil, http.StatusUnauthorized, errors.New("incorrect password")
+	}
+	return dbUser, http.StatusOK, nil
+}
+
+// authenticatedUser returns the user that userAuth authenticated
+func authenticatedUser(r *http.Request) *api.User {
+	u, _ := r.Context().Value(userKey{}).(*api.User)
+	return u
+}
+
+// userAuth requires the requests of the given methods to be sent by a user,
+// see requestUser. The user is passed on to the handler, which reads it with
+// authenticatedUser
+func userAuth(next http.Handler, scope string, methods ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if internal.Contains(methods, r.Method) {
+
+			err := r.ParseMultipartForm(10 << 20)
+
+			if err != nil {
+				err = r.ParseForm()
+				if err != nil {
+					http.Error(w, "invalid auth", http.StatusBadRequest)
+					return
+				}
+			}
+
+			dbUser, status, err := requestUser(r, scope)
+			if err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), userKey{}, dbUser))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serviceOrUserAuth lets through requests signed by another bundle service with
+// the service scope, any other request must come from a user, see userAuth
+func serviceOrUserAuth(next http.Handler, serviceScope string, scope string) http.Handler {
+	users := userAuth(next, scope, http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if checkScope(bearerToken(r), serviceScope) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		users.ServeHTTP(w, r)
+	})
+}
+
+func tokensHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	uscl := grpc.NewUserClient("", "")
+
+	switch r.Method {
+	case http.MethodGet:
+		claims, err := parseApiToken(bearerToken(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		dbUser, err := uscl.Get(&api.User{Id: claims.Subject})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		asJSON, err := json.Marshal(&ApiToken{
+			Username:  dbUser.Username,
+			Scopes:    claims.Scopes,
+			ExpiresAt: claims.ExpiresAt,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		internal.WriteResponse(w, string(asJSON), http.StatusOK)
+
+	case http.MethodPost:
+		err := r.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		scopes := r.Form["scope"]
+		if len(scopes) == 0 {
+			scopes = ApiTokenScopes
+		}
+		for _, v := range scopes {
+			if !internal.Contains(ApiTokenScopes, v) {
+				http.Error(w, "unknown scope "+v, http.StatusBadRequest)
+				return
+			}
+		}
+
+		dbUser, status, err := passwordUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		token, err := newApiToken(dbUser, scopes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		asJSON, err := json.Marshal(token)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		internal.WriteResponse(w, string(asJSON), http.StatusOK)
+
+	case http.MethodDelete:
+		claims, err := parseApiToken(bearerToken(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		sessions := grpc.NewSessionsClient("", "")
+		if err := sessions.Delete(&api.Session{Id: claims.Id}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
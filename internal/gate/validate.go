@@ -0,0 +1,233 @@
+package gate
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bennycio/bundle/api"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// maxJarSize is the largest jar that can be uploaded
+	maxJarSize = 100 << 20
+	// maxFormOverhead is how much the other fields of an upload form can add
+	// to the jar
+	maxFormOverhead = 1 << 20
+	// maxJarEntries is the most files that a jar can contain
+	maxJarEntries = 65535
+	// maxJarUncompressedSize is the most that all files of a jar together can
+	// take up once extracted
+	maxJarUncompressedSize = 512 << 20
+	// maxCompressionRatio is the most that a file larger than a megabyte can
+	// be compressed, anything more is a zip bomb
+	maxCompressionRatio = 100
+	// maxDescriptorSize is the largest plugin.yml that is read
+	maxDescriptorSize = 1 << 20
+)
+
+// FieldError is the reason that a field of an upload was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field of an upload that was rejected
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (v *ValidationError) Error() string {
+	msgs := []string{}
+	for _, e := range v.Errors {
+		msgs = append(msgs, e.Field+": "+e.Message)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (v *ValidationError) add(field string, format string, a ...interface{}) {
+	v.Errors = append(v.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, a...)})
+}
+
+// writeValidationError responds with the rejected fields as JSON
+func writeValidationError(w http.ResponseWriter, v *ValidationError) {
+	asJSON, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	w.Write(asJSON)
+}
+
+// pluginDescriptor is the part of a plugin.yml or bungee.yml that is checked
+type pluginDescriptor struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Main    string `yaml:"main"`
+	// APIVersion is the oldest Minecraft version that the plugin runs on
+	APIVersion string   `yaml:"api-version"`
+	Depend     []string `yaml:"depend"`
+	SoftDepend []string `yaml:"softdepend"`
+	LoadBefore []string `yaml:"loadbefore"`
+	Conflicts  []string `yaml:"conflicts"`
+}
+
+// validateJar opens an uploaded jar and checks that it is a readable zip
+// within the size limits. The plugin.yml, or bungee.yml, of a plugin must
+// match the name and version that the plugin is uploaded as and its main class
+// must be in the jar. Server software has no plugin.yml, so it is rejected if
+// it has one and its manifest must name a main class that is in the jar. No
+// descriptor is returned for server software
+func validateJar(rd io.ReaderAt, size int64, plugin *api.Plugin) (*pluginDescriptor, *ValidationError) {
+	result := &ValidationError{}
+
+	if size > maxJarSize {
+		result.add("plugin", "jar is larger than %d MB", maxJarSize>>20)
+		return nil, result
+	}
+
+	reader, err := zip.NewReader(rd, size)
+	if err != nil {
+		result.add("plugin", "not a readable jar: %s", err.Error())
+		return nil, result
+	}
+
+	if len(reader.File) > maxJarEntries {
+		result.add("plugin", "jar has more than %d files", maxJarEntries)
+		return nil, result
+	}
+
+	files := map[string]*zip.File{}
+	var total uint64
+	for _, f := range reader.File {
+		total += f.UncompressedSize64
+		if total > maxJarUncompressedSize {
+			result.add("plugin", "jar is larger than %d MB once extracted", maxJarUncompressedSize>>20)
+			return nil, result
+		}
+		if f.UncompressedSize64 > 1<<20 && f.UncompressedSize64 > f.CompressedSize64*maxCompressionRatio {
+			result.add("plugin", "%s is compressed more than %d times", f.Name, maxCompressionRatio)
+			return nil, result
+		}
+		files[f.Name] = f
+	}
+
+	descFile, ok := files["plugin.yml"]
+	if !ok {
+		descFile, ok = files["bungee.yml"]
+	}
+
+	if plugin.Type == api.ArtifactType_SERVER_SOFTWARE {
+		// server software skips the plugin.yml checks, so a plugin cannot be
+		// uploaded as server software to get around them
+		if ok {
+			result.add("type", "jar has a %s, upload it as a plugin", descFile.Name)
+			return nil, result
+		}
+		manifest, ok := files["META-INF/MANIFEST.MF"]
+		if !ok {
+			result.add("plugin", "server software has no META-INF/MANIFEST.MF")
+			return nil, result
+		}
+		main, err := readMainClass(manifest)
+		if err != nil {
+			result.add("plugin", "META-INF/MANIFEST.MF is invalid: %s", err.Error())
+		} else if main == "" {
+			result.add("main", "META-INF/MANIFEST.MF has no Main-Class")
+		} else if _, ok := files[strings.ReplaceAll(main, ".", "/")+".class"]; !ok {
+			result.add("main", "main class %s is not in the jar", main)
+		}
+		if len(result.Errors) > 0 {
+			return nil, result
+		}
+		return nil, nil
+	}
+
+	if !ok {
+		result.add("plugin", "jar has no plugin.yml or bungee.yml")
+		return nil, result
+	}
+
+	desc, err := readDescriptor(descFile)
+	if err != nil {
+		result.add("plugin", "%s is invalid: %s", descFile.Name, err.Error())
+		return nil, result
+	}
+
+	if desc.Name == "" {
+		result.add("name", "%s has no name", descFile.Name)
+	} else if desc.Name != plugin.Name {
+		result.add("name", "%s is named %s, not %s", descFile.Name, desc.Name, plugin.Name)
+	}
+	if desc.Version == "" {
+		result.add("version", "%s has no version", descFile.Name)
+	} else if desc.Version != plugin.Version {
+		result.add("version", "%s has version %s, not %s", descFile.Name, desc.Version, plugin.Version)
+	}
+	if desc.Main == "" {
+		result.add("main", "%s has no main class", descFile.Name)
+	} else if _, ok := files[strings.ReplaceAll(desc.Main, ".", "/")+".class"]; !ok {
+		result.add("main", "main class %s is not in the jar", desc.Main)
+	}
+
+	if len(result.Errors) > 0 {
+		return nil, result
+	}
+	return desc, nil
+}
+
+func readDescriptor(f *zip.File) (*pluginDescriptor, error) {
+	bs, err := readLimited(f)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pluginDescriptor{}
+	if err := yaml.Unmarshal(bs, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// readMainClass returns the Main-Class of a jar manifest, empty if it has none
+func readMainClass(f *zip.File) (string, error) {
+	bs, err := readLimited(f)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(bs), "\n") {
+		spl := strings.SplitN(strings.TrimRight(line, "\r"), ":", 2)
+		if len(spl) == 2 && strings.EqualFold(strings.TrimSpace(spl[0]), "Main-Class") {
+			return strings.TrimSpace(spl[1]), nil
+		}
+	}
+	return "", nil
+}
+
+// readLimited reads a small file of a jar, never more than maxDescriptorSize
+func readLimited(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxDescriptorSize {
+		return nil, fmt.Errorf("larger than %d KB", maxDescriptorSize>>10)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	// the declared size can be a lie, so never read more than the limit
+	bs, err := io.ReadAll(io.LimitReader(rc, maxDescriptorSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) > maxDescriptorSize {
+		return nil, fmt.Errorf("larger than %d KB", maxDescriptorSize>>10)
+	}
+	return bs, nil
+}
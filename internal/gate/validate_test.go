@@ -0,0 +1,245 @@
+package gate
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bennycio/bundle/api"
+)
+
+type jarEntry struct {
+	name string
+	body []byte
+}
+
+// buildJar zips the entries into a jar, deflating them like a build tool would
+func buildJar(t *testing.T, entries ...jarEntry) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(e.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func pluginYml(name, version, main string) jarEntry {
+	body := fmt.Sprintf("name: %s\nversion: %s\nmain: %s\napi-version: \"1.17\"\ndepend: [Vault]\nsoftdepend: [PlaceholderAPI]\n", name, version, main)
+	return jarEntry{"plugin.yml", []byte(body)}
+}
+
+func mainClass(main string) jarEntry {
+	return jarEntry{main, []byte{0xCA, 0xFE, 0xBA, 0xBE}}
+}
+
+func manifest(main string) jarEntry {
+	body := "Manifest-Version: 1.0\r\n"
+	if main != "" {
+		body += "Main-Class: " + main + "\r\n"
+	}
+	return jarEntry{"META-INF/MANIFEST.MF", []byte(body)}
+}
+
+func TestValidateJar(t *testing.T) {
+	plugin := &api.Plugin{Name: "Essentials", Version: "2.19.0"}
+	server := &api.Plugin{Name: "Paper", Version: "1.17.1", Type: api.ArtifactType_SERVER_SOFTWARE}
+
+	manyEntries := []jarEntry{pluginYml("Essentials", "2.19.0", "com.earth2me.Essentials"), mainClass("com/earth2me/Essentials.class")}
+	for i := 0; i < maxJarEntries; i++ {
+		manyEntries = append(manyEntries, jarEntry{fmt.Sprintf("assets/%d.txt", i), nil})
+	}
+
+	tests := []struct {
+		name   string
+		plugin *api.Plugin
+		jar    []byte
+		// size overrides the size of the jar when it is not zero
+		size   int64
+		fields []string
+	}{
+		{
+			name:   "valid plugin",
+			plugin: plugin,
+			jar:    buildJar(t, pluginYml("Essentials", "2.19.0", "com.earth2me.Essentials"), mainClass("com/earth2me/Essentials.class")),
+		},
+		{
+			name:   "valid bungee plugin",
+			plugin: plugin,
+			jar: buildJar(t,
+				jarEntry{"bungee.yml", []byte("name: Essentials\nversion: 2.19.0\nmain: com.earth2me.Essentials\n")},
+				mainClass("com/earth2me/Essentials.class")),
+		},
+		{
+			name:   "name mismatch",
+			plugin: plugin,
+			jar:    buildJar(t, pluginYml("EssentialsX", "2.19.0", "com.earth2me.Essentials"), mainClass("com/earth2me/Essentials.class")),
+			fields: []string{"name"},
+		},
+		{
+			name:   "version mismatch",
+			plugin: plugin,
+			jar:    buildJar(t, pluginYml("Essentials", "2.18.0", "com.earth2me.Essentials"), mainClass("com/earth2me/Essentials.class")),
+			fields: []string{"version"},
+		},
+		{
+			name:   "name and version mismatch",
+			plugin: plugin,
+			jar:    buildJar(t, pluginYml("EssentialsX", "2.18.0", "com.earth2me.Essentials"), mainClass("com/earth2me/Essentials.class")),
+			fields: []string{"name", "version"},
+		},
+		{
+			name:   "missing main class",
+			plugin: plugin,
+			jar:    buildJar(t, pluginYml("Essentials", "2.19.0", "com.earth2me.Essentials")),
+			fields: []string{"main"},
+		},
+		{
+			name:   "no main",
+			plugin: plugin,
+			jar:    buildJar(t, jarEntry{"plugin.yml", []byte("name: Essentials\nversion: 2.19.0\n")}),
+			fields: []string{"main"},
+		},
+		{
+			name:   "no plugin.yml",
+			plugin: plugin,
+			jar:    buildJar(t, mainClass("com/earth2me/Essentials.class")),
+			fields: []string{"plugin"},
+		},
+		{
+			name:   "invalid plugin.yml",
+			plugin: plugin,
+			jar:    buildJar(t, jarEntry{"plugin.yml", []byte("name: [Essentials\n")}),
+			fields: []string{"plugin"},
+		},
+		{
+			name:   "not a zip",
+			plugin: plugin,
+			jar:    []byte("not a jar"),
+			fields: []string{"plugin"},
+		},
+		{
+			name:   "too large",
+			plugin: plugin,
+			jar:    buildJar(t, pluginYml("Essentials", "2.19.0", "com.earth2me.Essentials"), mainClass("com/earth2me/Essentials.class")),
+			size:   maxJarSize + 1,
+			fields: []string{"plugin"},
+		},
+		{
+			name:   "too many entries",
+			plugin: plugin,
+			jar:    buildJar(t, manyEntries...),
+			fields: []string{"plugin"},
+		},
+		{
+			name:   "high compression ratio",
+			plugin: plugin,
+			jar: buildJar(t,
+				pluginYml("Essentials", "2.19.0", "com.earth2me.Essentials"),
+				mainClass("com/earth2me/Essentials.class"),
+				jarEntry{"bomb.bin", make([]byte, 4<<20)}),
+			fields: []string{"plugin"},
+		},
+		{
+			name:   "valid server software",
+			plugin: server,
+			jar:    buildJar(t, manifest("io.papermc.paperclip.Paperclip"), mainClass("io/papermc/paperclip/Paperclip.class")),
+		},
+		{
+			name:   "server software with a plugin.yml",
+			plugin: server,
+			jar: buildJar(t,
+				manifest("io.papermc.paperclip.Paperclip"),
+				mainClass("io/papermc/paperclip/Paperclip.class"),
+				pluginYml("Paper", "1.17.1", "io.papermc.paperclip.Paperclip")),
+			fields: []string{"type"},
+		},
+		{
+			name:   "server software with a bungee.yml",
+			plugin: server,
+			jar: buildJar(t,
+				manifest("io.papermc.paperclip.Paperclip"),
+				mainClass("io/papermc/paperclip/Paperclip.class"),
+				jarEntry{"bungee.yml", []byte("name: Paper\n")}),
+			fields: []string{"type"},
+		},
+		{
+			name:   "server software without a Main-Class",
+			plugin: server,
+			jar:    buildJar(t, manifest(""), mainClass("io/papermc/paperclip/Paperclip.class")),
+			fields: []string{"main"},
+		},
+		{
+			name:   "server software without a manifest",
+			plugin: server,
+			jar:    buildJar(t, mainClass("io/papermc/paperclip/Paperclip.class")),
+			fields: []string{"plugin"},
+		},
+		{
+			name:   "server software whose main class is missing",
+			plugin: server,
+			jar:    buildJar(t, manifest("io.papermc.paperclip.Paperclip")),
+			fields: []string{"main"},
+		},
+	}
+
+	for _, tt := range tests {
+		size := tt.size
+		if size == 0 {
+			size = int64(len(tt.jar))
+		}
+		desc, verr := validateJar(bytes.NewReader(tt.jar), size, tt.plugin)
+
+		fields := []string{}
+		if verr != nil {
+			for _, e := range verr.Errors {
+				fields = append(fields, e.Field)
+			}
+		}
+		want := tt.fields
+		if want == nil {
+			want = []string{}
+		}
+		if !reflect.DeepEqual(fields, want) {
+			t.Errorf("%s: rejected fields %v, want %v (%v)", tt.name, fields, want, verr)
+			continue
+		}
+
+		switch {
+		case verr != nil && desc != nil:
+			t.Errorf("%s: returned a descriptor along with errors", tt.name)
+		case verr == nil && tt.plugin.Type == api.ArtifactType_SERVER_SOFTWARE && desc != nil:
+			t.Errorf("%s: returned a descriptor for server software", tt.name)
+		case verr == nil && tt.plugin.Type != api.ArtifactType_SERVER_SOFTWARE && desc == nil:
+			t.Errorf("%s: returned no descriptor", tt.name)
+		}
+	}
+}
+
+func TestValidateJarDescriptor(t *testing.T) {
+	jar := buildJar(t, pluginYml("Essentials", "2.19.0", "com.earth2me.Essentials"), mainClass("com/earth2me/Essentials.class"))
+	desc, verr := validateJar(bytes.NewReader(jar), int64(len(jar)), &api.Plugin{Name: "Essentials", Version: "2.19.0"})
+	if verr != nil {
+		t.Fatalf("validateJar returned %v", verr)
+	}
+	if desc.APIVersion != "1.17" {
+		t.Errorf("APIVersion = %q, want 1.17", desc.APIVersion)
+	}
+	if !reflect.DeepEqual(desc.Depend, []string{"Vault"}) {
+		t.Errorf("Depend = %v, want [Vault]", desc.Depend)
+	}
+	if !reflect.DeepEqual(desc.SoftDepend, []string{"PlaceholderAPI"}) {
+		t.Errorf("SoftDepend = %v, want [PlaceholderAPI]", desc.SoftDepend)
+	}
+}
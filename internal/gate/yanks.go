@@ -0,0 +1,236 @@
+package gate
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/internal/gate/grpc"
+	"github.com/bennycio/bundle/internal/version"
+)
+
+// hasVersion reports whether a version of a plugin was ever uploaded, as far
+// as the plugin, its changelogs and its releases know
+func hasVersion(plugin *api.Plugin, changelogs *api.Changelogs, releases *api.Releases, version string) bool {
+	if plugin.Version != "" && plugin.Version == version {
+		return true
+	}
+	for _, v := range plugin.Channels {
+		if v == version {
+			return true
+		}
+	}
+	if _, ok := plugin.Yanked[version]; ok {
+		return true
+	}
+	if changelogs != nil {
+		for _, v := range changelogs.Changelogs {
+			if v.Version == version {
+				return true
+			}
+		}
+	}
+	if releases != nil {
+		for _, v := range releases.Releases {
+			if v.Version == version {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// latestVersion resolves latest to the newest stable version of a plugin that
+// was not yanked. When its current version was yanked the releases and
+// changelogs of the plugin are searched, like the CLI does. The status to
+// respond with is returned along with an error
+func latestVersion(plugin *api.Plugin) (string, int, error) {
+	if _, yanked := plugin.Yanked[plugin.Version]; plugin.Version != "" && !yanked {
+		return plugin.Version, http.StatusOK, nil
+	}
+
+	stable := map[string]bool{}
+	if plugin.Version != "" {
+		stable[plugin.Version] = true
+	}
+	if v := plugin.Channels[version.Stable]; v != "" {
+		stable[v] = true
+	}
+	releases, err := grpc.NewPluginClient("", "").GetReleases(&api.Release{PluginId: plugin.Id})
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	for _, v := range releases.Releases {
+		if version.ChannelIncludes(version.Stable, v.Channel) {
+			stable[v.Version] = true
+		}
+	}
+	if changelogs, err := grpc.NewChangelogsClient("", "").GetAll(&api.Changelog{PluginId: plugin.Id}); err == nil {
+		for _, v := range changelogs.Changelogs {
+			if version.ChannelIncludes(version.Stable, v.Channel) {
+				stable[v.Version] = true
+			}
+		}
+	}
+
+	versions := []string{}
+	for v := range stable {
+		if _, yanked := plugin.Yanked[v]; !yanked {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		if len(stable) > 0 {
+			return "", http.StatusGone, fmt.Errorf("every stable release of %s was yanked", plugin.Name)
+		}
+		return "", http.StatusNotFound, fmt.Errorf("%s has no stable releases, ask for a version of one of its channels", plugin.Name)
+	}
+	result, err := version.Latest(versions, "")
+	if err != nil {
+		return "", http.StatusNotFound, fmt.Errorf("%s: %s", plugin.Name, err.Error())
+	}
+	return result, http.StatusOK, nil
+}
+
+// authoredPlugin looks up the plugin named in a request and makes sure that
+// the authenticated user, or the user that another service asks for, is its
+// author
+func authoredPlugin(w http.ResponseWriter, r *http.Request) (*api.Plugin, bool) {
+	dbcl := grpc.NewPluginClient("", "")
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "specify a plugin", http.StatusBadRequest)
+		return nil, false
+	}
+	dbPl, err := dbcl.Get(&api.Plugin{Name: name})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return nil, false
+	}
+	dbUser := authenticatedUser(r)
+	if dbUser == nil && r.FormValue("user") != "" {
+		dbUser, err = grpc.NewUserClient("", "").Get(&api.User{Id: r.FormValue("user")})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	if dbUser == nil || dbPl.Author == nil || dbPl.Author.Id != dbUser.Id {
+		http.Error(w, "you are not the author of this plugin", http.StatusForbidden)
+		return nil, false
+	}
+	return dbPl, true
+}
+
+func yanksHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	dbcl := grpc.NewPluginClient("", "")
+
+	switch r.Method {
+	case http.MethodPost, http.MethodDelete:
+		dbPl, ok := authoredPlugin(w, r)
+		if !ok {
+			return
+		}
+
+		version := r.FormValue("version")
+		reason := r.FormValue("reason")
+
+		verr := &ValidationError{}
+		if version == "" {
+			verr.add("version", "specify the version to yank")
+		}
+		if r.Method == http.MethodPost && reason == "" {
+			verr.add("reason", "give a reason so that users know why the version was yanked")
+		}
+		if len(verr.Errors) > 0 {
+			writeValidationError(w, verr)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			if _, yanked := dbPl.Yanked[version]; !yanked {
+				http.Error(w, fmt.Sprintf("version %s is not yanked", version), http.StatusNotFound)
+				return
+			}
+			// an empty reason takes the version off the list
+			reason = ""
+		} else {
+			changelogs, _ := grpc.NewChangelogsClient("", "").GetAll(&api.Changelog{PluginId: dbPl.Id})
+			releases, _ := dbcl.GetReleases(&api.Release{PluginId: dbPl.Id})
+			if !hasVersion(dbPl, changelogs, releases, version) {
+				http.Error(w, fmt.Sprintf("%s has no version %s", dbPl.Name, version), http.StatusNotFound)
+				return
+			}
+		}
+
+		err := dbcl.UpdateYanked(&api.Plugin{Id: dbPl.Id, Yanked: map[string]string{version: reason}})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func deprecationsHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	dbcl := grpc.NewPluginClient("", "")
+
+	switch r.Method {
+	case http.MethodPost:
+		dbPl, ok := authoredPlugin(w, r)
+		if !ok {
+			return
+		}
+
+		dep := &api.Deprecation{
+			Reason:      r.FormValue("reason"),
+			Replacement: r.FormValue("replacement"),
+		}
+
+		verr := &ValidationError{}
+		if dep.Reason == "" {
+			verr.add("reason", "give a reason so that users know why the plugin was deprecated")
+		}
+		if dep.Replacement != "" {
+			replacement, err := dbcl.Get(&api.Plugin{Name: dep.Replacement})
+			if err != nil {
+				verr.add("replacement", "there is no plugin named %s", dep.Replacement)
+			} else if replacement.Id == dbPl.Id {
+				verr.add("replacement", "a plugin cannot replace itself")
+			} else {
+				dep.Replacement = replacement.Name
+			}
+		}
+		if len(verr.Errors) > 0 {
+			writeValidationError(w, verr)
+			return
+		}
+
+		err := dbcl.UpdateDeprecation(&api.Plugin{Id: dbPl.Id, Deprecation: dep})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodDelete:
+		dbPl, ok := authoredPlugin(w, r)
+		if !ok {
+			return
+		}
+		if dbPl.Deprecation == nil {
+			http.Error(w, dbPl.Name+" is not deprecated", http.StatusNotFound)
+			return
+		}
+
+		// an empty reason removes the deprecation
+		err := dbcl.UpdateDeprecation(&api.Plugin{Id: dbPl.Id, Deprecation: &api.Deprecation{}})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -36,6 +36,7 @@ func (s *sessionsServer) Get(ctx context.Context, req *api.Session) (*api.Sessio
 		Id:            ses.Id,
 		UserId:        ses.UserId,
 		LastRetrieved: time.Now().Unix(),
+		ExpiresAt:     ses.ExpiresAt,
 	}
 
 	bs, err := json.Marshal(copy)
@@ -67,7 +68,12 @@ func (s *sessionsServer) Insert(ctx context.Context, req *api.Session) (*api.Ses
 		return nil, err
 	}
 
-	err = s.client.Set(ctx, req.Id, string(bs), 24*time.Hour).Err()
+	ttl := 24 * time.Hour
+	if req.ExpiresAt > 0 {
+		ttl = time.Until(time.Unix(req.ExpiresAt, 0))
+	}
+
+	err = s.client.Set(ctx, req.Id, string(bs), ttl).Err()
 	if err != nil {
 		logger.ErrLog.Print(err.Error())
 		return nil, err
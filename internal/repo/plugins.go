@@ -1,20 +1,44 @@
 package repo
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/bennycio/bundle/api"
+	"github.com/bennycio/bundle/internal"
 	"github.com/bennycio/bundle/logger"
 )
 
+// checksumMetadataKey is the object metadata that holds the SHA-256 digest of
+// a jar
+const checksumMetadataKey = "Sha256"
+
+// repoBucket is the bucket that jars are uploaded to
+const repoBucket = "bundle-repository"
+
+// stagingPrefix is where uploaded jars wait until the gate commits them
+const stagingPrefix = "staging"
+
+// ErrVersionExists is returned when a version is committed that is already
+// stored with another jar
+var ErrVersionExists = errors.New("version already exists, versions cannot be replaced")
+
+var errChecksumMismatch = errors.New("jar does not match its checksum")
+
 func pluginsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
@@ -33,15 +57,19 @@ func pluginsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			Version: r.FormValue("version"),
 		}
 
-		pl, err := downloadPluginFromRepo(req)
+		dl, err := downloadPluginFromRepo(req, r.Header.Get("Range"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
 			return
 		}
+		defer dl.Body.Close()
 
-		logger.DebugLog.Printf("downloading %v", req)
+		logger.DebugLog.Printf("downloading %v from byte %d", req, dl.Offset)
 
-		w.Write(pl)
+		dl.WriteHeaders(w)
+		if _, err := io.Copy(w, dl.Body); err != nil {
+			logger.ErrLog.Print(err.Error())
+		}
 	case http.MethodPost:
 
 		err := r.ParseMultipartForm(32 << 20)
@@ -70,51 +98,316 @@ func pluginsHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		loc, err := uploadPluginToRepo(req, f)
+		key, err := stagePlugin(req, f, h.Size, r.FormValue("sha256"))
+		if err == errChecksumMismatch {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		logger.InfoLog.Printf("uploaded plugin with id: %s to %s", r.FormValue("id"), loc)
+		logger.InfoLog.Printf("staged plugin with id: %s at %s", req.Id, key)
+		internal.WriteResponse(w, key, http.StatusOK)
+	case http.MethodDelete:
+		req := &api.Plugin{
+			Id: r.FormValue("id"),
+			Author: &api.User{
+				Id: r.FormValue("author"),
+			},
+			Version: r.FormValue("version"),
+		}
+
+		err := removePlugin(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.InfoLog.Printf("removed version %s of plugin with id: %s", req.Version, req.Id)
+	}
+
+}
+
+func commitHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := &api.Plugin{
+		Id: r.FormValue("id"),
+		Author: &api.User{
+			Id: r.FormValue("author"),
+		},
+		Version: r.FormValue("version"),
+	}
+
+	err := commitPlugin(req, r.FormValue("key"))
+	if err == ErrVersionExists {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logger.InfoLog.Printf("committed version %s of plugin with id: %s", req.Version, req.Id)
+}
+
+func stagingHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := &api.Plugin{
+		Id: r.FormValue("id"),
+		Author: &api.User{
+			Id: r.FormValue("author"),
+		},
+		Version: r.FormValue("version"),
+	}
+
+	err := abortPlugin(req, r.FormValue("key"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+}
+
+// pluginKey is where the jar of a version is stored once it is committed
+func pluginKey(plugin *api.Plugin) string {
+	return filepath.Join(plugin.Author.Id, plugin.Id, plugin.Version, plugin.Id+".jar")
+}
 
+// stagingDir is where the jars of a version wait until one is committed
+func stagingDir(plugin *api.Plugin) string {
+	return filepath.Join(stagingPrefix, plugin.Author.Id, plugin.Id, plugin.Version) + "/"
 }
 
-func uploadPluginToRepo(plugin *api.Plugin, file io.Reader) (string, error) {
+// stagePlugin stores a jar under a staging key of its own and reads it back to
+// make sure that it was stored whole. The digest, when given, is what the
+// sender computed and must match what was received
+func stagePlugin(plugin *api.Plugin, file io.ReadSeeker, size int64, digest string) (string, error) {
+	if plugin.Id == "" || plugin.Version == "" || plugin.Author == nil || plugin.Author.Id == "" {
+		return "", errors.New("id, author and version required")
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if digest != "" && !strings.EqualFold(digest, sum) {
+		return "", errChecksumMismatch
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	key := stagingDir(plugin) + hex.EncodeToString(token) + ".jar"
 
 	sess, err := session.NewSession(&aws.Config{Region: aws.String(os.Getenv("AWS_REGION"))})
 	if err != nil {
 		return "", err
 	}
 
-	fp := filepath.Join(plugin.Author.Id, plugin.Id, plugin.Version, plugin.Id+".jar")
 	uploader := s3manager.NewUploader(sess)
-	result, err := uploader.Upload(&s3manager.UploadInput{
+	_, err = uploader.Upload(&s3manager.UploadInput{
 		Body:   file,
-		Bucket: aws.String("bundle-repository"),
-		Key:    aws.String(fp),
+		Bucket: aws.String(repoBucket),
+		Key:    aws.String(key),
+		Metadata: map[string]*string{
+			checksumMetadataKey: aws.String(sum),
+		},
 	})
 	if err != nil {
 		return "", err
 	}
-	return result.Location, nil
+
+	svc := s3.New(sess)
+	stored, err := headObject(svc, key)
+	if err == nil && (stored.ContentLength == nil || *stored.ContentLength != size || objectChecksum(stored) != sum) {
+		err = fmt.Errorf("staged jar of %s %s does not match the upload", plugin.Id, plugin.Version)
+	}
+	if err != nil {
+		deleteObject(svc, key)
+		return "", err
+	}
+	return key, nil
+}
+
+// commitPlugin moves a staged jar to where the version is downloaded from.
+// The gate records the release before it commits, so only one upload of a
+// version ever gets here. A version that is already stored with another jar,
+// from before releases were recorded, is never replaced
+func commitPlugin(plugin *api.Plugin, key string) error {
+	if plugin.Id == "" || plugin.Version == "" || plugin.Author == nil || plugin.Author.Id == "" {
+		return errors.New("id, author and version required")
+	}
+	if !strings.HasPrefix(key, stagingDir(plugin)) {
+		return errors.New("not a staged jar of this version")
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(os.Getenv("AWS_REGION"))})
+	if err != nil {
+		return err
+	}
+	svc := s3.New(sess)
+
+	staged, err := headObject(svc, key)
+	if err != nil {
+		return err
+	}
+
+	final := pluginKey(plugin)
+	existing, err := headObject(svc, final)
+	if err == nil {
+		deleteObject(svc, key)
+		if objectChecksum(existing) != objectChecksum(staged) {
+			return ErrVersionExists
+		}
+		return nil
+	}
+	if !isNotFound(err) {
+		return err
+	}
+
+	_, err = svc.CopyObject(&s3.CopyObjectInput{
+		Bucket:     aws.String(repoBucket),
+		CopySource: aws.String(url.PathEscape(repoBucket + "/" + key)),
+		Key:        aws.String(final),
+	})
+	if err != nil {
+		return err
+	}
+	deleteObject(svc, key)
+	return nil
+}
+
+// removePlugin removes the committed jar of a version whose release could not
+// be recorded
+func removePlugin(plugin *api.Plugin) error {
+	if plugin.Id == "" || plugin.Version == "" || plugin.Author == nil || plugin.Author.Id == "" {
+		return errors.New("id, author and version required")
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(os.Getenv("AWS_REGION"))})
+	if err != nil {
+		return err
+	}
+	_, err = s3.New(sess).DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(repoBucket),
+		Key:    aws.String(pluginKey(plugin)),
+	})
+	return err
+}
+
+// abortPlugin removes a staged jar that will not be committed
+func abortPlugin(plugin *api.Plugin, key string) error {
+	if plugin.Id == "" || plugin.Version == "" || plugin.Author == nil || plugin.Author.Id == "" {
+		return errors.New("id, author and version required")
+	}
+	if !strings.HasPrefix(key, stagingDir(plugin)) {
+		return errors.New("not a staged jar of this version")
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(os.Getenv("AWS_REGION"))})
+	if err != nil {
+		return err
+	}
+	_, err = s3.New(sess).DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(repoBucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func headObject(svc *s3.S3, key string) (*s3.HeadObjectOutput, error) {
+	return svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(repoBucket),
+		Key:    aws.String(key),
+	})
+}
+
+// deleteObject removes an object that is no longer needed, a failure only
+// leaves garbage behind so it is logged
+func deleteObject(svc *s3.S3, key string) {
+	_, err := svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(repoBucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+	}
+}
+
+func objectChecksum(out *s3.HeadObjectOutput) string {
+	for k, v := range out.Metadata {
+		if strings.EqualFold(k, checksumMetadataKey) && v != nil {
+			return *v
+		}
+	}
+	return ""
 }
 
-func downloadPluginFromRepo(plugin *api.Plugin) ([]byte, error) {
+func isNotFound(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code() == "NotFound" || aerr.Code() == s3.ErrCodeNoSuchKey
+	}
+	return false
+}
 
-	sess, _ := session.NewSession(&aws.Config{Region: aws.String(os.Getenv("AWS_REGION"))})
+// downloadPluginFromRepo opens a stored jar. The range is an HTTP Range header
+// and may be empty to read the whole jar
+func downloadPluginFromRepo(plugin *api.Plugin, byteRange string) (*internal.Download, error) {
 
-	fn := filepath.Join(plugin.Author.Id, plugin.Id, plugin.Version, plugin.Id+".jar")
-	buf := aws.NewWriteAtBuffer([]byte{})
-	downloader := s3manager.NewDownloader(sess)
-	_, err := downloader.Download(buf, &s3.GetObjectInput{
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(os.Getenv("AWS_REGION"))})
+	if err != nil {
+		return nil, err
+	}
+
+	fn := pluginKey(plugin)
+	input := &s3.GetObjectInput{
 		Bucket: aws.String(os.Getenv("AWS_BUCKET")),
 		Key:    aws.String(fn),
-	})
+	}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+
+	out, err := s3.New(sess).GetObject(input)
 	if err != nil {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	dl := &internal.Download{
+		Body:   out.Body,
+		Length: -1,
+		Size:   -1,
+	}
+	if out.ContentLength != nil {
+		dl.Length = *out.ContentLength
+		dl.Size = *out.ContentLength
+	}
+	if out.ContentRange != nil {
+		dl.Offset, dl.Size, err = internal.ParseContentRange(*out.ContentRange)
+		if err != nil {
+			out.Body.Close()
+			return nil, err
+		}
+	}
+	for k, v := range out.Metadata {
+		if strings.EqualFold(k, checksumMetadataKey) && v != nil {
+			dl.Sha256 = *v
+		}
+	}
+	return dl, nil
 }
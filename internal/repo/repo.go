@@ -9,9 +9,13 @@ import (
 func NewRepoServer() *http.Server {
 	mux := http.NewServeMux()
 	pluginsHandler := http.HandlerFunc(pluginsHandlerFunc)
+	commitHandler := http.HandlerFunc(commitHandlerFunc)
+	stagingHandler := http.HandlerFunc(stagingHandlerFunc)
 	thumbnailsHandler := http.HandlerFunc(thumbnailsHandlerFunc)
 
 	mux.Handle("/repo/plugins", pluginsHandler)
+	mux.Handle("/repo/plugins/commit", commitHandler)
+	mux.Handle("/repo/plugins/staging", stagingHandler)
 	mux.Handle("/repo/thumbnails", thumbnailsHandler)
 
 	return internal.MakeServerFromMux(mux)
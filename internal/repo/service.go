@@ -15,8 +15,11 @@ import (
 )
 
 type repoService interface {
-	DownloadPlugin(plugin *api.Plugin) ([]byte, error)
-	UploadPlugin(user *api.User, plugin *api.Plugin, data io.Reader) error
+	DownloadPlugin(plugin *api.Plugin, byteRange string) (*internal.Download, error)
+	StagePlugin(plugin *api.Plugin, data io.Reader, digest string) (string, error)
+	CommitPlugin(plugin *api.Plugin, key string) error
+	AbortPlugin(plugin *api.Plugin, key string) error
+	RemovePlugin(plugin *api.Plugin) error
 	UploadThumbnail(user *api.User, plugin *api.Plugin, data io.Reader) error
 }
 
@@ -38,7 +41,10 @@ func NewRepoService(host string, port string) repoService {
 	}
 }
 
-func (r *repoServiceImpl) DownloadPlugin(plugin *api.Plugin) ([]byte, error) {
+// DownloadPlugin opens a stream of a stored jar. The range is passed on as the
+// Range header of the request and may be empty. The caller is responsible for
+// closing the Body of the download
+func (r *repoServiceImpl) DownloadPlugin(plugin *api.Plugin, byteRange string) (*internal.Download, error) {
 
 	scheme := "https://"
 	u, err := url.Parse(fmt.Sprintf("%s%s:%s/repo/plugins", scheme, r.Host, r.Port))
@@ -57,14 +63,17 @@ func (r *repoServiceImpl) DownloadPlugin(plugin *api.Plugin) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	if internal.IsRespError(resp) {
+		defer resp.Body.Close()
 		buf := &bytes.Buffer{}
 		_, err = io.Copy(buf, resp.Body)
 		if err != nil {
@@ -72,57 +81,130 @@ func (r *repoServiceImpl) DownloadPlugin(plugin *api.Plugin) ([]byte, error) {
 		}
 		return nil, errors.New(buf.String())
 	}
-	bs := &bytes.Buffer{}
-	_, err = io.Copy(bs, resp.Body)
+
+	dl, err := internal.DownloadFromResponse(resp)
 	if err != nil {
+		resp.Body.Close()
 		return nil, err
 	}
-	return bs.Bytes(), nil
+	return dl, nil
 }
 
-func (r *repoServiceImpl) UploadPlugin(user *api.User, plugin *api.Plugin, data io.Reader) error {
+// StagePlugin uploads a jar for a version of a plugin to a staging key of its
+// own and returns that key. The digest is the hex SHA-256 of the jar, the jar
+// is rejected if it arrives with another. Nothing can download a staged jar
+// until it is committed
+func (r *repoServiceImpl) StagePlugin(plugin *api.Plugin, data io.Reader, digest string) (string, error) {
 
 	scheme := "https://"
 
 	u, err := url.Parse(fmt.Sprintf("%s%s:%s/repo/plugins", scheme, r.Host, r.Port))
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
 	if plugin.Id == "" || plugin.Version == "" || plugin.Author == nil {
-		return errors.New("missing required fields")
+		return "", errors.New("missing required fields")
 	}
 
 	writer.WriteField("id", plugin.Id)
 	writer.WriteField("version", plugin.Version)
 	writer.WriteField("author", plugin.Author.Id)
+	writer.WriteField("sha256", digest)
 
 	part, err := writer.CreateFormFile("plugin", plugin.Id)
 	if err != nil {
-		return err
+		return "", err
 	}
 	_, err = io.Copy(part, data)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	err = writer.Close()
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	client := internal.NewTlsClient()
 
 	req, err := http.NewRequest(http.MethodPost, u.String(), body)
 	if err != nil {
-		return err
+		return "", err
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	buf := &bytes.Buffer{}
+	_, err = io.Copy(buf, resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if internal.IsRespError(resp) {
+		return "", errors.New(buf.String())
+	}
+
+	return buf.String(), nil
+}
+
+// CommitPlugin makes the jar staged under the key the one that the version of
+// the plugin is downloaded as. It returns ErrVersionExists if the version is
+// already stored with another jar
+func (r *repoServiceImpl) CommitPlugin(plugin *api.Plugin, key string) error {
+	return r.sendStaged(http.MethodPost, "/repo/plugins/commit", plugin, key)
+}
+
+// AbortPlugin removes a staged jar that will not be committed
+func (r *repoServiceImpl) AbortPlugin(plugin *api.Plugin, key string) error {
+	return r.sendStaged(http.MethodDelete, "/repo/plugins/staging", plugin, key)
+}
+
+// RemovePlugin deletes the committed jar of a version, to undo a commit when
+// the release cannot be recorded
+func (r *repoServiceImpl) RemovePlugin(plugin *api.Plugin) error {
+	return r.sendStaged(http.MethodDelete, "/repo/plugins", plugin, "")
+}
+
+func (r *repoServiceImpl) sendStaged(method string, path string, plugin *api.Plugin, key string) error {
+
+	scheme := "https://"
+
+	u, err := url.Parse(fmt.Sprintf("%s%s:%s%s", scheme, r.Host, r.Port, path))
+	if err != nil {
+		return err
+	}
+
+	if plugin.Id == "" || plugin.Version == "" || plugin.Author == nil {
+		return errors.New("missing required fields")
+	}
+
+	q := u.Query()
+	q.Add("id", plugin.Id)
+	q.Add("author", plugin.Author.Id)
+	q.Add("version", plugin.Version)
+	if key != "" {
+		q.Add("key", key)
+	}
+	u.RawQuery = q.Encode()
+
+	client := internal.NewTlsClient()
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -130,6 +212,9 @@ func (r *repoServiceImpl) UploadPlugin(user *api.User, plugin *api.Plugin, data
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusConflict {
+		return ErrVersionExists
+	}
 	if internal.IsRespError(resp) {
 		buf := &bytes.Buffer{}
 		_, err = io.Copy(buf, resp.Body)
@@ -5,7 +5,7 @@ import (
 	"os"
 )
 
-var validStatusCodes = []int{http.StatusOK, http.StatusAccepted, http.StatusCreated, http.StatusFound, http.StatusSeeOther, http.StatusProcessing, http.StatusContinue}
+var validStatusCodes = []int{http.StatusOK, http.StatusPartialContent, http.StatusAccepted, http.StatusCreated, http.StatusFound, http.StatusSeeOther, http.StatusProcessing, http.StatusContinue}
 
 func IsValidPath(path string) bool {
 	_, err := os.Stat(path)
@@ -0,0 +1,59 @@
+package version
+
+import "strings"
+
+const (
+	Stable = "stable"
+	Beta   = "beta"
+	Alpha  = "alpha"
+)
+
+// Channels are the release channels that a version can be published to, from
+// the most to the least stable. Opting in to a channel also opts in to every
+// channel before it, so beta includes stable releases
+var Channels = []string{Stable, Beta, Alpha}
+
+// ParseChannel returns the release channel of that name, ignoring case, and
+// whether it is one
+func ParseChannel(s string) (string, bool) {
+	for _, v := range Channels {
+		if strings.EqualFold(v, strings.TrimSpace(s)) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// SplitChannel splits a constraint such as "^2.1@beta" into its range and its
+// release channel. A bare channel such as "beta" is the newest version of that
+// channel. The channel is empty when the constraint does not name one
+func SplitChannel(constraint string) (string, string) {
+	if ch, ok := ParseChannel(constraint); ok {
+		return "", ch
+	}
+	if i := strings.LastIndex(constraint, "@"); i >= 0 {
+		if ch, ok := ParseChannel(constraint[i+1:]); ok {
+			return strings.TrimSpace(constraint[:i]), ch
+		}
+	}
+	return constraint, ""
+}
+
+// ChannelIncludes reports whether opting in to a channel includes the releases
+// of another channel. Releases without a channel are stable
+func ChannelIncludes(optIn string, channel string) bool {
+	return channelRank(channel) <= channelRank(optIn)
+}
+
+func channelRank(channel string) int {
+	ch, ok := ParseChannel(channel)
+	if !ok {
+		return 0
+	}
+	for i, v := range Channels {
+		if v == ch {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,217 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a set of version ranges such as "^2.1", "~1.4" or
+// ">=1.3 <2.0". Ranges separated by "||" are alternatives and comparators
+// separated by spaces or commas must all match. A bare version must match
+// exactly. A release channel can follow the ranges, as in "^2.1@beta"
+type Constraint struct {
+	sets     [][]comparator
+	original string
+	// Channel is the release channel that the constraint opts in to, empty
+	// for stable releases only
+	Channel string
+}
+
+type comparator struct {
+	op string
+	v  Version
+}
+
+var operators = []string{">=", "<=", "!=", "==", "~>", ">", "<", "=", "^", "~"}
+
+func ParseConstraint(s string) (*Constraint, error) {
+	result := &Constraint{original: strings.TrimSpace(s)}
+	s, result.Channel = SplitChannel(s)
+
+	for _, group := range strings.Split(s, "||") {
+		set, err := parseSet(group)
+		if err != nil {
+			return nil, err
+		}
+		result.sets = append(result.sets, set)
+	}
+
+	return result, nil
+}
+
+func parseSet(s string) ([]comparator, error) {
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+
+	// allow a space between an operator and its version, e.g. ">= 1.3"
+	tokens := []string{}
+	for i := 0; i < len(fields); i++ {
+		if isOperator(fields[i]) && i+1 < len(fields) {
+			tokens = append(tokens, fields[i]+fields[i+1])
+			i++
+			continue
+		}
+		tokens = append(tokens, fields[i])
+	}
+
+	if len(tokens) == 0 {
+		return []comparator{}, nil
+	}
+
+	result := []comparator{}
+	for _, t := range tokens {
+		cs, err := parseComparator(t)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, cs...)
+	}
+	return result, nil
+}
+
+func isOperator(s string) bool {
+	for _, v := range operators {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func parseComparator(s string) ([]comparator, error) {
+	if s == "*" || s == "x" || s == "X" || strings.EqualFold(s, "latest") {
+		return []comparator{}, nil
+	}
+
+	op := ""
+	for _, v := range operators {
+		if strings.HasPrefix(s, v) {
+			op = v
+			break
+		}
+	}
+	raw := strings.TrimPrefix(s, op)
+
+	raw, wildcard := trimWildcards(raw)
+	if wildcard && raw == "" {
+		return []comparator{}, nil
+	}
+
+	v, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if wildcard {
+		if op != "" && op != "=" && op != "==" {
+			return nil, fmt.Errorf("wildcards cannot be used with %s", op)
+		}
+		return []comparator{{">=", v}, {"<", bump(v, len(v.Segments)-1)}}, nil
+	}
+
+	switch op {
+	case "^":
+		i := 0
+		for i < len(v.Segments)-1 && v.Segments[i] == 0 {
+			i++
+		}
+		return []comparator{{">=", v}, {"<", bump(v, i)}}, nil
+	case "~", "~>":
+		i := 1
+		if len(v.Segments) < 2 {
+			i = 0
+		}
+		return []comparator{{">=", v}, {"<", bump(v, i)}}, nil
+	case "", "==":
+		op = "="
+	}
+
+	return []comparator{{op, v}}, nil
+}
+
+// trimWildcards removes trailing "x" or "*" segments from a version
+func trimWildcards(s string) (string, bool) {
+	wildcard := false
+	for {
+		switch {
+		case strings.HasSuffix(s, ".x"), strings.HasSuffix(s, ".X"), strings.HasSuffix(s, ".*"):
+			s = s[:len(s)-2]
+			wildcard = true
+		case s == "x" || s == "X" || s == "*":
+			return "", true
+		default:
+			return s, wildcard
+		}
+	}
+}
+
+// bump returns the smallest version that is newer than every version sharing
+// the segments of v up to and including index i
+func bump(v Version, i int) Version {
+	segs := make([]int, i+1)
+	copy(segs, v.Segments)
+	segs[i]++
+	return Version{Segments: segs}
+}
+
+func (c comparator) check(v Version) bool {
+	cmp := v.Compare(c.v)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// Check reports whether v satisfies the constraint. Pre-releases only match a
+// range that names a pre-release of the same version, so "^2.1" never selects
+// 2.2.0-beta but ">=2.2.0-alpha <2.3" and "^2.1@beta" do
+func (c *Constraint) Check(v Version) bool {
+	prerelease := c.Channel != "" && c.Channel != Stable
+	for _, set := range c.sets {
+		if checkSet(set, v, prerelease) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkSet(set []comparator, v Version, prerelease bool) bool {
+	// a channel other than stable opts in to pre-releases whose release
+	// matches, so "^2.0@beta" selects 2.0.0-beta but "^1@beta" never does
+	if prerelease && v.IsPrerelease() && checkSet(set, Version{Segments: v.Segments}, false) {
+		return true
+	}
+
+	for _, cmp := range set {
+		if !cmp.check(v) {
+			return false
+		}
+	}
+
+	if !v.IsPrerelease() {
+		return true
+	}
+	for _, cmp := range set {
+		if cmp.v.IsPrerelease() && cmp.v.sameRelease(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Constraint) String() string {
+	if c.original == "" {
+		return "latest"
+	}
+	return c.original
+}
@@ -0,0 +1,210 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a loosely parsed semantic version. Plugin authors rarely follow
+// semver to the letter, so any number of numeric segments is accepted and a
+// leading "v" is ignored. Anything after a "-" is treated as a pre-release and
+// anything after a "+" is build metadata that does not affect ordering
+type Version struct {
+	Segments   []int
+	Prerelease []string
+	original   string
+}
+
+func Parse(s string) (Version, error) {
+	original := s
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+
+	if i := strings.IndexAny(s, "+ "); i >= 0 {
+		s = s[:i]
+	}
+
+	result := Version{original: original}
+
+	if i := strings.Index(s, "-"); i >= 0 {
+		if pre := s[i+1:]; pre != "" {
+			result.Prerelease = strings.Split(pre, ".")
+		}
+		s = s[:i]
+	}
+
+	if s == "" {
+		return Version{}, fmt.Errorf("invalid version %q", original)
+	}
+
+	for _, v := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", original)
+		}
+		result.Segments = append(result.Segments, n)
+	}
+
+	return result, nil
+}
+
+func (v Version) String() string {
+	if v.original != "" {
+		return v.original
+	}
+	segs := make([]string, len(v.Segments))
+	for i, s := range v.Segments {
+		segs[i] = strconv.Itoa(s)
+	}
+	s := strings.Join(segs, ".")
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	return s
+}
+
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+func (v Version) segment(i int) int {
+	if i < len(v.Segments) {
+		return v.Segments[i]
+	}
+	return 0
+}
+
+// Compare returns -1, 0 or 1 if v is older than, the same as or newer than o.
+// Missing segments count as zero, so 1.2 and 1.2.0 are the same version
+func (v Version) Compare(o Version) int {
+	n := len(v.Segments)
+	if len(o.Segments) > n {
+		n = len(o.Segments)
+	}
+	for i := 0; i < n; i++ {
+		a, b := v.segment(i), o.segment(i)
+		if a != b {
+			if a > b {
+				return 1
+			}
+			return -1
+		}
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// sameRelease reports whether two versions share every numeric segment
+func (v Version) sameRelease(o Version) bool {
+	return Version{Segments: v.Segments}.Compare(Version{Segments: o.Segments}) == 0
+}
+
+func comparePrerelease(a, b []string) int {
+	// a release is always newer than any of its pre-releases
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		an, aErr := strconv.Atoi(a[i])
+		bn, bErr := strconv.Atoi(b[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an > bn {
+					return 1
+				}
+				return -1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(strings.ToLower(a[i]), strings.ToLower(b[i])); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(a) > len(b):
+		return 1
+	case len(a) < len(b):
+		return -1
+	}
+	return 0
+}
+
+// Compare compares two version strings. Versions that cannot be parsed are
+// ordered before valid versions and compared as plain strings with each other
+func Compare(a, b string) int {
+	av, aErr := Parse(a)
+	bv, bErr := Parse(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return av.Compare(bv)
+	case aErr == nil:
+		return 1
+	case bErr == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// Latest returns the newest version out of versions that satisfies the
+// constraint. An empty constraint or "latest" selects the newest stable version
+func Latest(versions []string, constraint string) (string, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		// not a range, so the only possible match is the literal version
+		literal, _ := SplitChannel(constraint)
+		for _, v := range versions {
+			if v == strings.TrimSpace(literal) {
+				return v, nil
+			}
+		}
+		return "", err
+	}
+
+	var best *Version
+	for _, s := range versions {
+		v, err := Parse(s)
+		if err != nil {
+			continue
+		}
+		if !c.Check(v) {
+			continue
+		}
+		if best == nil || v.Compare(*best) > 0 {
+			cp := v
+			best = &cp
+		}
+	}
+
+	if best == nil {
+		return "", errors.New("no version satisfies " + c.String())
+	}
+	return best.String(), nil
+}
+
+// Satisfies reports whether the version matches the constraint. Constraints
+// that are not ranges must match the version literally
+func Satisfies(version, constraint string) bool {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		literal, _ := SplitChannel(constraint)
+		return strings.TrimSpace(literal) == version
+	}
+	v, err := Parse(version)
+	if err != nil {
+		return false
+	}
+	return c.Check(v)
+}
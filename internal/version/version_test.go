@@ -0,0 +1,301 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in         string
+		segments   []int
+		prerelease []string
+		err        bool
+	}{
+		{"1.2.3", []int{1, 2, 3}, nil, false},
+		{"v2.0", []int{2, 0}, nil, false},
+		{"V3", []int{3}, nil, false},
+		{" 1.4.0 ", []int{1, 4, 0}, nil, false},
+		{"1.2.3.4", []int{1, 2, 3, 4}, nil, false},
+		{"2.0.0-beta.1", []int{2, 0, 0}, []string{"beta", "1"}, false},
+		{"1.0.0+build.5", []int{1, 0, 0}, nil, false},
+		{"1.0.0-rc.2+build", []int{1, 0, 0}, []string{"rc", "2"}, false},
+		{"1.0-", []int{1, 0}, nil, false},
+		{"", nil, nil, true},
+		{"latest", nil, nil, true},
+		{"1.x", nil, nil, true},
+		{"1..2", nil, nil, true},
+		{"-beta", nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", tt.in, v.Segments)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) returned %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(v.Segments, tt.segments) {
+			t.Errorf("Parse(%q).Segments = %v, want %v", tt.in, v.Segments, tt.segments)
+		}
+		if !reflect.DeepEqual(v.Prerelease, tt.prerelease) {
+			t.Errorf("Parse(%q).Prerelease = %v, want %v", tt.in, v.Prerelease, tt.prerelease)
+		}
+		if v.String() != tt.in {
+			t.Errorf("Parse(%q).String() = %q", tt.in, v.String())
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"v1.2.0", "1.2", 0},
+		{"1.10", "1.9", 1},
+		{"1.2.3", "1.2.4", -1},
+		{"2", "1.99.99", 1},
+		{"1.0.0+a", "1.0.0+b", 0},
+
+		// pre-releases
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-beta", "1.0.0-rc", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.10", -1},
+		{"1.0.0-beta", "1.0.0-beta.1", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-Beta", "1.0.0-beta", 0},
+		{"1.0.1-alpha", "1.0.0", 1},
+
+		// versions that cannot be parsed are ordered first
+		{"snapshot", "0.0.1", -1},
+		{"1.0", "snapshot", 1},
+		{"abc", "abd", -1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		in      string
+		channel string
+		str     string
+		err     bool
+	}{
+		{"", "", "latest", false},
+		{"latest", "", "latest", false},
+		{"*", "", "*", false},
+		{"^2.1", "", "^2.1", false},
+		{"~1.4", "", "~1.4", false},
+		{"~> 1.4", "", "~> 1.4", false},
+		{">= 1.3 < 2.0", "", ">= 1.3 < 2.0", false},
+		{">=1.3, <2.0", "", ">=1.3, <2.0", false},
+		{"1.x || 3.2.*", "", "1.x || 3.2.*", false},
+		{"^2.1@beta", Beta, "^2.1@beta", false},
+		{"^2.1@ALPHA", Alpha, "^2.1@ALPHA", false},
+		{"beta", Beta, "beta", false},
+		{"stable", Stable, "stable", false},
+		{"1.2@nightly", "", "", true},
+		{"banana", "", "", true},
+		{"^1.x", "", "", true},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseConstraint(%q) = %v, want an error", tt.in, c)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) returned %v", tt.in, err)
+			continue
+		}
+		if c.Channel != tt.channel {
+			t.Errorf("ParseConstraint(%q).Channel = %q, want %q", tt.in, c.Channel, tt.channel)
+		}
+		if c.String() != tt.str {
+			t.Errorf("ParseConstraint(%q).String() = %q, want %q", tt.in, c.String(), tt.str)
+		}
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version, constraint string
+		want                bool
+	}{
+		{"1.2.3", "", true},
+		{"1.2.3", "latest", true},
+		{"1.2.3", "*", true},
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3", "=1.2.3", true},
+		{"1.2.3", "1.2.4", false},
+		{"1.2.0", "1.2", true},
+		{"1.2.3", "!=1.2.3", false},
+
+		// caret and tilde
+		{"2.5.0", "^2.1", true},
+		{"3.0.0", "^2.1", false},
+		{"2.0.9", "^2.1", false},
+		{"0.2.5", "^0.2.1", true},
+		{"0.3.0", "^0.2.1", false},
+		{"1.4.9", "~1.4", true},
+		{"1.5.0", "~1.4", false},
+		{"1.4.2", "~> 1.4.1", true},
+		{"1.5.0", "~> 1.4.1", false},
+		{"2.0", "~2", true},
+		{"3.0", "~2", false},
+
+		// comparators and alternatives
+		{"1.3", ">= 1.3 < 2.0", true},
+		{"2.0", ">=1.3, <2.0", false},
+		{"1.2.9", ">1.2.8 <=1.3", true},
+		{"1.9.0", "1.x || 3.2.*", true},
+		{"3.2.7", "1.x || 3.2.*", true},
+		{"3.3.0", "1.x || 3.2.*", false},
+
+		// pre-releases are only selected when opted in to
+		{"2.2.0-beta", "^2.1", false},
+		{"2.2.0-beta", "latest", false},
+		{"2.2.0-beta", ">=2.2.0-alpha <2.3", true},
+		{"2.2.0-beta", "2.2.0-beta", true},
+		{"2.2.1-beta", ">=2.2.0-alpha <2.3", false},
+
+		// release channels
+		{"2.2.0-beta", "^2.1@beta", true},
+		{"2.2.0-alpha", "^2.1@alpha", true},
+		{"2.2.0", "^2.1@beta", true},
+		{"3.0.0-beta", "^2.1@beta", false},
+		{"2.0.0-beta", "^1@beta", false},
+		{"2.0.0-beta", "^2.0@beta", true},
+		{"2.0.0-beta", "^2.0", false},
+		{"2.2.0-beta", "beta", true},
+		{"2.2.0-beta", "^2.1@stable", false},
+		{"2.2.0", "^2.1@stable", true},
+
+		// constraints that are not ranges match literally
+		{"snapshot", "snapshot", true},
+		{"snapshot", "other", false},
+		{"snapshot", "^1.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := Satisfies(tt.version, tt.constraint); got != tt.want {
+			t.Errorf("Satisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	versions := []string{"1.0", "1.2.0", "1.10.1", "2.0.0-alpha.1", "2.0.0-beta", "1.11.0-rc.1", "snapshot", "0.9"}
+
+	tests := []struct {
+		constraint string
+		want       string
+		err        bool
+	}{
+		{"", "1.10.1", false},
+		{"latest", "1.10.1", false},
+		{"^1.0", "1.10.1", false},
+		{"~1.2", "1.2.0", false},
+		{"<1.0", "0.9", false},
+		{"1.2", "1.2.0", false},
+		{"beta", "2.0.0-beta", false},
+		{"alpha", "2.0.0-beta", false},
+		{"stable", "1.10.1", false},
+		{"^1.0@beta", "1.11.0-rc.1", false},
+		{"^2.0@alpha", "2.0.0-beta", false},
+		{">=2.0.0-alpha <2.0.0-beta", "2.0.0-alpha.1", false},
+		{"snapshot", "snapshot", false},
+		{"^3.0", "", true},
+		{"^2.0", "", true},
+		{"missing", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := Latest(versions, tt.constraint)
+		if tt.err {
+			if err == nil {
+				t.Errorf("Latest(%q) = %q, want an error", tt.constraint, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Latest(%q) returned %v", tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Latest(%q) = %q, want %q", tt.constraint, got, tt.want)
+		}
+	}
+
+	if _, err := Latest(nil, ""); err == nil {
+		t.Error("Latest of no versions returned no error")
+	}
+}
+
+func TestSplitChannel(t *testing.T) {
+	tests := []struct {
+		in, rng, channel string
+	}{
+		{"^2.1@beta", "^2.1", Beta},
+		{"^2.1 @ Alpha", "^2.1", Alpha},
+		{"beta", "", Beta},
+		{" STABLE ", "", Stable},
+		{"^2.1", "^2.1", ""},
+		{"1.0@nightly", "1.0@nightly", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		rng, ch := SplitChannel(tt.in)
+		if rng != tt.rng || ch != tt.channel {
+			t.Errorf("SplitChannel(%q) = %q, %q, want %q, %q", tt.in, rng, ch, tt.rng, tt.channel)
+		}
+	}
+}
+
+func TestChannelIncludes(t *testing.T) {
+	tests := []struct {
+		optIn, channel string
+		want           bool
+	}{
+		{Stable, Stable, true},
+		{Stable, Beta, false},
+		{Stable, Alpha, false},
+		{Beta, Stable, true},
+		{Beta, Beta, true},
+		{Beta, Alpha, false},
+		{Alpha, Stable, true},
+		{Alpha, Beta, true},
+		{Alpha, Alpha, true},
+		{"", "", true},
+		{"", Beta, false},
+		{Beta, "", true},
+		{"BETA", "beta", true},
+	}
+
+	for _, tt := range tests {
+		if got := ChannelIncludes(tt.optIn, tt.channel); got != tt.want {
+			t.Errorf("ChannelIncludes(%q, %q) = %v, want %v", tt.optIn, tt.channel, got, tt.want)
+		}
+	}
+}
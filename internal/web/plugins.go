@@ -8,6 +8,7 @@ import (
 
 	"github.com/bennycio/bundle/api"
 	"github.com/bennycio/bundle/internal/gate"
+	"github.com/bennycio/bundle/internal/version"
 	"github.com/bennycio/bundle/logger"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/russross/blackfriday/v2"
@@ -111,6 +112,12 @@ func pluginsHandlerFunc(w http.ResponseWriter, req *http.Request) {
 			}
 
 			data.Plugin = plugin
+			data.Channels = pluginChannels(plugin)
+
+			releases, err := gs.GetReleases(plugin)
+			if err == nil {
+				data.Releases = releases.Releases
+			}
 		}
 	}
 
@@ -122,6 +129,37 @@ func pluginsHandlerFunc(w http.ResponseWriter, req *http.Request) {
 
 }
 
+// channelRelease is the newest version of a release channel
+type channelRelease struct {
+	Channel string
+	Version string
+}
+
+// pluginChannels lists the newest version of every release channel of a
+// plugin, leaving out the channels that are behind its newest stable version
+func pluginChannels(plugin *api.Plugin) []channelRelease {
+	stable := plugin.Channels[version.Stable]
+	if stable == "" && (plugin.Channel == "" || plugin.Channel == version.Stable) {
+		stable = plugin.Version
+	}
+
+	result := []channelRelease{}
+	for _, ch := range version.Channels {
+		v := plugin.Channels[ch]
+		if ch == version.Stable {
+			v = stable
+		}
+		if v == "" {
+			continue
+		}
+		if ch != version.Stable && stable != "" && version.Compare(v, stable) <= 0 {
+			continue
+		}
+		result = append(result, channelRelease{Channel: ch, Version: v})
+	}
+	return result
+}
+
 func thumbnailHandlerFunc(w http.ResponseWriter, req *http.Request) {
 
 	prof, err := getProfFromCookie(req)
@@ -289,3 +327,95 @@ func premiumHandlerFunc(w http.ResponseWriter, req *http.Request) {
 		http.Redirect(w, req, req.Header.Get("Referer"), http.StatusFound)
 	}
 }
+
+// authoredPlugin looks up the plugin posted by a form and makes sure that the
+// logged in user is its author
+func authoredPlugin(w http.ResponseWriter, req *http.Request) (*api.Plugin, bool) {
+	prof, err := getProfFromCookie(req)
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		handleError(w, err, http.StatusUnauthorized)
+		return nil, false
+	}
+
+	if req.Method != http.MethodPost {
+		err := fmt.Errorf("only method post allowed")
+		logger.ErrLog.Print(err.Error())
+		handleError(w, err, http.StatusBadRequest)
+		return nil, false
+	}
+
+	plugin := req.FormValue("plugin")
+	if prof.Id == "" || plugin == "" {
+		err = fmt.Errorf("user and plugin must be specified")
+		logger.ErrLog.Print(err.Error())
+		handleError(w, err, http.StatusBadRequest)
+		return nil, false
+	}
+
+	dbpl, err := gate.NewGateService("", "").GetPlugin(&api.Plugin{Id: plugin})
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		handleError(w, err, http.StatusBadRequest)
+		return nil, false
+	}
+	if dbpl.Author == nil || dbpl.Author.Id != prof.Id {
+		err = fmt.Errorf("must be plugin author")
+		logger.ErrLog.Print(err.Error())
+		handleError(w, err, http.StatusBadRequest)
+		return nil, false
+	}
+	return dbpl, true
+}
+
+func yankHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	dbpl, ok := authoredPlugin(w, req)
+	if !ok {
+		return
+	}
+	gs := gate.NewGateService("", "")
+
+	plugin := &api.Plugin{Name: dbpl.Name, Version: req.FormValue("version")}
+	reason := req.FormValue("reason")
+
+	var err error
+	if req.FormValue("undo") == "true" {
+		err = gs.UnyankVersion(dbpl.Author, plugin)
+	} else {
+		err = gs.YankVersion(dbpl.Author, plugin, reason)
+	}
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		handleError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, req, req.Header.Get("Referer"), http.StatusFound)
+}
+
+func deprecateHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	dbpl, ok := authoredPlugin(w, req)
+	if !ok {
+		return
+	}
+	gs := gate.NewGateService("", "")
+
+	plugin := &api.Plugin{Name: dbpl.Name}
+
+	var err error
+	if req.FormValue("undo") == "true" {
+		err = gs.UndeprecatePlugin(dbpl.Author, plugin)
+	} else {
+		err = gs.DeprecatePlugin(dbpl.Author, plugin, &api.Deprecation{
+			Reason:      req.FormValue("reason"),
+			Replacement: req.FormValue("replacement"),
+		})
+	}
+	if err != nil {
+		logger.ErrLog.Print(err.Error())
+		handleError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, req, req.Header.Get("Referer"), http.StatusFound)
+}
@@ -29,6 +29,8 @@ type templateData struct {
 	Profile         profile
 	Plugin          *api.Plugin
 	Plugins         []*api.Plugin
+	Channels        []channelRelease
+	Releases        []*api.Release
 	PurchaseSession string
 	Page            int
 	Functions       functions
@@ -43,6 +45,7 @@ type functions struct {
 	Date     func(int64) string
 	Contains func([]string, string) bool
 	FmtMoney func(int) string
+	FmtSize  func(int64) string
 }
 
 var tpl *template.Template
@@ -78,11 +81,22 @@ func fillFunctions(data templateData) templateData {
 		return strings.TrimRight(s, "0")
 	}
 
+	size := func(n int64) string {
+		switch {
+		case n >= 1<<20:
+			return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+		case n >= 1<<10:
+			return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+		}
+		return fmt.Sprintf("%d B", n)
+	}
+
 	data.Functions = functions{
 		Math:     math,
 		Date:     date,
 		Contains: contains,
 		FmtMoney: money,
+		FmtSize:  size,
 	}
 	return data
 
@@ -22,6 +22,8 @@ func NewWebServer() *http.Server {
 	stripeReturnHandler := http.HandlerFunc(stripeReturnHandlerFunc)
 	purchasePluginHandler := http.HandlerFunc(purchasePluginHandlerFunc)
 	premiumHandler := http.HandlerFunc(premiumHandlerFunc)
+	yankHandler := http.HandlerFunc(yankHandlerFunc)
+	deprecateHandler := http.HandlerFunc(deprecateHandlerFunc)
 
 	mux.Handle("/", rootHandler)
 	mux.Handle("/about", aboutHandler)
@@ -30,6 +32,8 @@ func NewWebServer() *http.Server {
 	mux.Handle("/plugins/thumbnails", thumbnailHandler)
 	mux.Handle("/plugins/purchase", purchasePluginHandler)
 	mux.Handle("/plugins/premium", premiumHandler)
+	mux.Handle("/plugins/yank", yankHandler)
+	mux.Handle("/plugins/deprecate", deprecateHandler)
 	mux.Handle("/profile", loginGate(profileHandler))
 	mux.Handle("/stripe/auth", stripeAuthHandler)
 	mux.Handle("/stripe/return", stripeReturnHandler)